@@ -1,5 +1,13 @@
 package go_requests
 
+import (
+	"context"
+	"encoding/base64"
+)
+
+// Authorization is the interface for the value of the Authorization header.
+// It can be attached to every request of a client with Builder.SetAuthorization,
+// or to a single request with WithAuthorization.
 type Authorization interface {
 	//	Basic sets the authorization to basic
 	Bearer(token string) Authorization
@@ -7,6 +15,10 @@ type Authorization interface {
 	// Basic sets the authorization to basic
 	Basic(username, password string) Authorization
 
+	// Digest sets the authorization to digest.
+	// The header is computed for every request from the challenge sent by the server.
+	Digest(username, password string) Authorization
+
 	// String returns the string representation of the authorization
 	String() string
 
@@ -22,6 +34,9 @@ type Authorization interface {
 	// IsBearer returns true if the authorization is bearer
 	IsBearer() bool
 
+	// IsDigest returns true if the authorization is digest
+	IsDigest() bool
+
 	// IsEmpty returns true if the authorization is empty
 	IsEmpty() bool
 
@@ -37,25 +52,43 @@ const (
 	AuthorizationTypeBasic AuthorizationType = "Basic"
 	//AuthorizationTypeBearer is the bearer authorization type
 	AuthorizationTypeBearer AuthorizationType = "Bearer"
+	//AuthorizationTypeDigest is the digest authorization type
+	AuthorizationTypeDigest AuthorizationType = "Digest"
 )
 
 // authorizationImpl is the implementation of the authorization interface
 type authorizationImpl struct {
 	authorizationType AuthorizationType
 	value             string
+	// username and password are kept for digest authorization, which needs them to answer every challenge
+	username string
+	password string
 }
 
 // Bearer sets the authorization to bearer
 func (a *authorizationImpl) Bearer(token string) Authorization {
 	a.authorizationType = AuthorizationTypeBearer
 	a.value = token
+	a.username, a.password = "", ""
 	return a
 }
 
-// Basic sets the authorization to basic
+// Basic sets the authorization to basic.
+// The credentials are base64 encoded as required by RFC 7617.
 func (a *authorizationImpl) Basic(username, password string) Authorization {
 	a.authorizationType = AuthorizationTypeBasic
-	a.value = username + ":" + password
+	a.value = base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	a.username, a.password = "", ""
+	return a
+}
+
+// Digest sets the authorization to digest (RFC 7616).
+// The value of the authorization is the username, the Authorization header is computed by the client
+// for every request from the WWW-Authenticate challenge of the server.
+func (a *authorizationImpl) Digest(username, password string) Authorization {
+	a.authorizationType = AuthorizationTypeDigest
+	a.value = username
+	a.username, a.password = username, password
 	return a
 }
 
@@ -84,6 +117,11 @@ func (a *authorizationImpl) IsBearer() bool {
 	return a.authorizationType == AuthorizationTypeBearer
 }
 
+// IsDigest returns true if the authorization is digest
+func (a *authorizationImpl) IsDigest() bool {
+	return a.authorizationType == AuthorizationTypeDigest
+}
+
 // IsEmpty returns true if the authorization is empty
 func (a *authorizationImpl) IsEmpty() bool {
 	return a.authorizationType == "" && a.value == ""
@@ -98,3 +136,38 @@ func (a *authorizationImpl) IsSet() bool {
 func NewAuthorization() Authorization {
 	return &authorizationImpl{}
 }
+
+// authorizationContextKey is the context key of the per request authorization
+type authorizationContextKey struct{}
+
+// WithAuthorization returns a copy of ctx that carries the given authorization.
+// Requests made with the returned context use it instead of the authorization of the Builder.
+//
+//	Example:
+//		ctx := go_requests.WithAuthorization(context.Background(), go_requests.NewAuthorization().Bearer(token))
+//		response, err := client.GetWithContext(ctx, "https://api.github.com/user")
+func WithAuthorization(ctx context.Context, authorization Authorization) context.Context {
+	return context.WithValue(ctx, authorizationContextKey{}, authorization)
+}
+
+// authorizationFromContext returns the authorization carried by ctx, if any.
+func authorizationFromContext(ctx context.Context) Authorization {
+	authorization, _ := ctx.Value(authorizationContextKey{}).(Authorization)
+	return authorization
+}
+
+// explicitAuthorizationContextKey is the context key that marks a request whose Authorization header was set by the
+// caller, with a header or an authorization
+type explicitAuthorizationContextKey struct{}
+
+// withExplicitAuthorization returns a copy of ctx that marks the Authorization header of the request as set by the
+// caller.
+func withExplicitAuthorization(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explicitAuthorizationContextKey{}, true)
+}
+
+// hasExplicitAuthorization returns true if the Authorization header of the request of ctx was set by the caller.
+func hasExplicitAuthorization(ctx context.Context) bool {
+	explicit, _ := ctx.Value(explicitAuthorizationContextKey{}).(bool)
+	return explicit
+}
@@ -1,6 +1,9 @@
 package go_requests
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 )
@@ -65,13 +68,13 @@ func Test_authorizationImpl_Basic(t *testing.T) {
 			name:   "test1",
 			fields: fields{authorizationType: AuthorizationTypeBasic, value: "test"},
 			args:   args{username: "test", password: "test"},
-			want:   &authorizationImpl{authorizationType: AuthorizationTypeBasic, value: "test:test"},
+			want:   &authorizationImpl{authorizationType: AuthorizationTypeBasic, value: "dGVzdDp0ZXN0"},
 		},
 		{
 			name:   "test2",
 			fields: fields{authorizationType: AuthorizationTypeBasic, value: "test"},
 			args:   args{username: "", password: ""},
-			want:   &authorizationImpl{authorizationType: AuthorizationTypeBasic, value: ":"},
+			want:   &authorizationImpl{authorizationType: AuthorizationTypeBasic, value: "Og=="},
 		},
 	}
 	for _, tt := range tests {
@@ -365,7 +368,7 @@ func TestNewAuthorization(t *testing.T) {
 			name: "test1",
 			want: &authorizationImpl{
 				authorizationType: AuthorizationTypeBasic,
-				value:             "user:password",
+				value:             "dXNlcjpwYXNzd29yZA==",
 			},
 		},
 	}
@@ -377,3 +380,96 @@ func TestNewAuthorization(t *testing.T) {
 		})
 	}
 }
+
+func Test_goHTTPClient_getAuthorization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		builder Authorization
+		ctx     Authorization
+		headers http.Header
+		want    string
+	}{
+		{
+			name: "none",
+			want: "",
+		},
+		{
+			name:    "builder basic",
+			builder: NewAuthorization().Basic("Aladdin", "open sesame"),
+			want:    "Basic QWxhZGRpbjpvcGVuIHNlc2FtZQ==",
+		},
+		{
+			name:    "builder bearer",
+			builder: NewAuthorization().Bearer("token"),
+			want:    "Bearer token",
+		},
+		{
+			name:    "request header overrides builder",
+			builder: NewAuthorization().Bearer("token"),
+			headers: http.Header{"Authorization": []string{"Bearer other"}},
+			want:    "Bearer other",
+		},
+		{
+			name:    "context overrides builder and header",
+			builder: NewAuthorization().Bearer("token"),
+			ctx:     NewAuthorization().Bearer("context"),
+			headers: http.Header{"Authorization": []string{"Bearer other"}},
+			want:    "Bearer context",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder()
+			builder.SetAuthorization(tt.builder)
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = WithAuthorization(ctx, tt.ctx)
+			}
+			res, err := builder.Build().GetWithContext(ctx, server.URL, tt.headers)
+			if err != nil {
+				t.Fatalf("GetWithContext() error = %v", err)
+			}
+			if res.String() != tt.want {
+				t.Errorf("Authorization header = %v, want %v", res.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_authorizationImpl_Digest(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		password string
+		want     Authorization
+	}{
+		{
+			name:     "test1",
+			username: "user",
+			password: "password",
+			want: &authorizationImpl{
+				authorizationType: AuthorizationTypeDigest,
+				value:             "user",
+				username:          "user",
+				password:          "password",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAuthorization().Digest(tt.username, tt.password)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("authorizationImpl.Digest() = %v, want %v", got, tt.want)
+			}
+			if !got.IsDigest() || got.IsBasic() || got.IsBearer() {
+				t.Errorf("authorizationImpl.Digest() has type %v", got.Type())
+			}
+		})
+	}
+}
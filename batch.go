@@ -0,0 +1,137 @@
+package go_requests
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultBatchConcurrency is the default number of requests of a batch sent at the same time
+const defaultBatchConcurrency = 10
+
+// BatchResult is the outcome of a request of a Batch.
+type BatchResult struct {
+	// Request is the request, as added to the batch.
+	Request Request
+	// Response is the response of the request, nil if it failed.
+	Response *Response
+	// Err is the error of the request. The requests that a fail-fast batch did not send have a context.Canceled error.
+	Err error
+}
+
+// Batch sends many requests with a Client, a bounded number of them at the same time.
+// The results are in the order of the requests, whatever order they complete in.
+//
+//	Example:
+//		batch := go_requests.NewBatch(client).SetConcurrency(8)
+//		for _, id := range ids {
+//			batch.Add(go_requests.NewRequest(http.MethodGet, "/pet/{petId}").SetPathParam("petId", id))
+//		}
+//		results, err := batch.Execute(ctx)
+//		for _, result := range results {
+//			if result.Err != nil {
+//				...
+//			}
+//		}
+type Batch interface {
+	// SetConcurrency sets how many requests are sent at the same time. Values lower than 1 are treated as 1.
+	SetConcurrency(concurrency int) Batch
+	// SetFailFast sets whether the batch stops at the first request that fails, canceling the requests in progress
+	// and skipping the others. By default, every request is sent and its error is collected in its result.
+	SetFailFast(failFast bool) Batch
+	// Add adds requests to the batch. A request may be added several times, unless its body is read from an io.Reader,
+	// with SetBodyReader or a reader part of a Multipart, since such a body can only be sent once.
+	Add(requests ...Request) Batch
+	// Len returns the number of requests of the batch.
+	Len() int
+	// Execute sends the requests of the batch and returns their results, in the order of the requests.
+	// A fail-fast batch returns the first error of a request, otherwise the error is nil and the errors of the
+	// requests are in their results. Requests fail with a transport error or a canceled context, not with an
+	// unsuccessful status code.
+	Execute(ctx context.Context) ([]BatchResult, error)
+}
+
+// batchImpl is the implementation of the Batch interface
+type batchImpl struct {
+	client      Client
+	concurrency int
+	failFast    bool
+	requests    []Request
+}
+
+// NewBatch returns an empty Batch that sends its requests with client.
+func NewBatch(client Client) Batch {
+	return &batchImpl{
+		client:      client,
+		concurrency: defaultBatchConcurrency,
+	}
+}
+
+// SetConcurrency sets how many requests are sent at the same time.
+func (b *batchImpl) SetConcurrency(concurrency int) Batch {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	b.concurrency = concurrency
+	return b
+}
+
+// SetFailFast sets whether the batch stops at the first request that fails.
+func (b *batchImpl) SetFailFast(failFast bool) Batch {
+	b.failFast = failFast
+	return b
+}
+
+// Add adds requests to the batch.
+func (b *batchImpl) Add(requests ...Request) Batch {
+	b.requests = append(b.requests, requests...)
+	return b
+}
+
+// Len returns the number of requests of the batch.
+func (b *batchImpl) Len() int {
+	return len(b.requests)
+}
+
+// Execute sends the requests of the batch with a pool of workers.
+func (b *batchImpl) Execute(ctx context.Context) ([]BatchResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]BatchResult, len(b.requests))
+	indexes := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	workers := b.concurrency
+	if workers > len(b.requests) {
+		workers = len(b.requests)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				result := &results[index]
+				result.Request = b.requests[index]
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+					continue
+				}
+				result.Response, result.Err = b.client.DoWithContext(ctx, result.Request)
+				if result.Err != nil && b.failFast {
+					once.Do(func() {
+						firstErr = result.Err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	for index := range b.requests {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+	return results, firstErr
+}
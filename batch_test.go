@@ -0,0 +1,135 @@
+package go_requests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_batchImpl_Execute(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		// the first requests are the slowest, so that they complete last
+		id, _ := strconv.Atoi(r.URL.Path[len("/pet/"):])
+		time.Sleep(time.Duration(20-id) * time.Millisecond)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+	builder := NewBuilder()
+	builder.SetBaseURL(server.URL)
+	batch := NewBatch(builder.Build()).SetConcurrency(4)
+	for i := 0; i < 20; i++ {
+		batch.Add(NewRequest(http.MethodGet, "/pet/{id}").SetPathParam("id", strconv.Itoa(i)))
+	}
+	if batch.Len() != 20 {
+		t.Fatalf("Len() = %d, want 20", batch.Len())
+	}
+	results, err := batch.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		if want := "/pet/" + strconv.Itoa(i); result.Err != nil || result.Response.String() != want {
+			t.Errorf("results[%d] = %v, %v, want %s", i, result.Response, result.Err, want)
+		}
+	}
+	if got := atomic.LoadInt32(&maxInFlight); got > 4 {
+		t.Errorf("requests in flight = %d, want at most 4", got)
+	}
+}
+
+func Test_batchImpl_Execute_errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}))
+	defer server.Close()
+	client := NewBuilder().Build()
+	requests := func() []Request {
+		return []Request{
+			NewRequest(http.MethodGet, server.URL+"/slow"),
+			NewRequest(http.MethodGet, "http://127.0.0.1:0/unreachable"),
+			NewRequest(http.MethodGet, server.URL+"/ok"),
+			NewRequest(http.MethodGet, server.URL+"/ok"),
+		}
+	}
+
+	t.Run("collect all", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		results, err := NewBatch(client).SetConcurrency(2).Add(requests()...).Execute(ctx)
+		if err != nil {
+			t.Fatalf("Execute() error = %v, want nil", err)
+		}
+		if results[1].Err == nil || results[2].Err != nil || results[3].Err != nil {
+			t.Errorf("errors = %v, %v, %v, want only the unreachable request to fail", results[1].Err, results[2].Err, results[3].Err)
+		}
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		start := time.Now()
+		results, err := NewBatch(client).SetConcurrency(2).SetFailFast(true).Add(requests()...).Execute(context.Background())
+		if err == nil || err != results[1].Err {
+			t.Fatalf("Execute() error = %v, want the error of the unreachable request", err)
+		}
+		if !errors.Is(results[0].Err, context.Canceled) {
+			t.Errorf("results[0].Err = %v, want the slow request canceled", results[0].Err)
+		}
+		for i, result := range results[2:] {
+			if !errors.Is(result.Err, context.Canceled) {
+				t.Errorf("results[%d].Err = %v, want the request skipped", i+2, result.Err)
+			}
+			if result.Request == nil {
+				t.Errorf("results[%d].Request = nil", i+2)
+			}
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Execute() took %v, want the slow request canceled", elapsed)
+		}
+	})
+}
+
+func Test_batchImpl_Execute_sameRequest(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+	req := NewRequest(http.MethodPost, server.URL).SetBody([]byte("hello"))
+	results, err := NewBatch(NewBuilder().Build()).SetConcurrency(4).Add(req, req, req, req).Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("results[%d].Err = %v, want nil", i, result.Err)
+		}
+	}
+	if fmt.Sprint(bodies) != "[hello hello hello hello]" {
+		t.Errorf("bodies = %q, want the body sent 4 times", bodies)
+	}
+}
@@ -7,11 +7,23 @@ import (
 
 // builderImpl is the implementation of the Builder interface and is used to build a client with the desired configuration.
 type builderImpl struct {
-	header    Headers
-	Timeout   Timeout
-	State     chan string
-	client    *goHTTPClient
-	cstClient *http.Client
+	header         Headers
+	Timeout        Timeout
+	State          chan string
+	client         *goHTTPClient
+	cstClient      *http.Client
+	retryPolicy    RetryPolicy
+	middlewares    []Middleware
+	authorization  Authorization
+	tokenSource    TokenSource
+	sigV4Signer    SigV4Signer
+	baseURL        string
+	cookieJar      http.CookieJar
+	redirectPolicy RedirectPolicy
+	cacheStorage   CacheStorage
+	rateLimiter    RateLimiter
+	circuitBreaker CircuitBreaker
+	hedgePolicy    HedgePolicy
 }
 
 // Builder is the interface that wraps the basic Build method. The Build method returns a Client.
@@ -29,6 +41,30 @@ type Builder interface {
 	Build() Client
 	//SetHTTPClient sets the http client to be used for the request instead of the default one.
 	SetHTTPClient(*http.Client)
+	//SetRetryPolicy sets the policy used to retry failed requests. A nil policy disables retries.
+	SetRetryPolicy(policy RetryPolicy)
+	//Use appends middlewares to the chain that wraps every request made by the Client.
+	Use(middlewares ...Middleware)
+	//SetAuthorization sets the authorization sent with every request made by the Client.
+	SetAuthorization(authorization Authorization)
+	//SetTokenSource sets the OAuth2 token source used to authorize every request made by the Client.
+	SetTokenSource(source TokenSource)
+	//SetSigV4Signer sets the signer used to sign every request made by the Client with AWS Signature Version 4.
+	SetSigV4Signer(signer SigV4Signer)
+	//SetBaseURL sets the URL that the relative URLs of the requests made by the Client are resolved against.
+	SetBaseURL(baseURL string)
+	//SetCookieJar sets the jar that stores the cookies received by the Client and sends them with its requests.
+	SetCookieJar(jar http.CookieJar)
+	//SetRedirectPolicy sets the policy that decides which redirects the Client follows.
+	SetRedirectPolicy(policy RedirectPolicy)
+	//SetCache sets the storage of the HTTP cache that answers the GET requests made by the Client when it can.
+	SetCache(storage CacheStorage)
+	//SetRateLimiter sets the limiter that throttles the requests made by the Client.
+	SetRateLimiter(limiter RateLimiter)
+	//SetCircuitBreaker sets the circuit breaker that stops the Client from sending requests to failing hosts.
+	SetCircuitBreaker(breaker CircuitBreaker)
+	//SetHedgePolicy sets the policy that hedges the safe requests made by the Client.
+	SetHedgePolicy(policy HedgePolicy)
 }
 
 // SetMaxIdleConnections sets the maximum number of idle (keep-alive) connections across all hosts.
@@ -77,6 +113,133 @@ func (b *builderImpl) SetHTTPClient(c *http.Client) {
 	return
 }
 
+// SetRetryPolicy sets the policy used to retry failed requests.
+// Without a policy, which is the default, every request is attempted exactly once.
+//
+//	Example:
+//		builder.SetRetryPolicy(go_requests.NewRetryPolicy().SetMaxAttempts(5))
+func (b *builderImpl) SetRetryPolicy(policy RetryPolicy) {
+	b.retryPolicy = policy
+}
+
+// Use appends middlewares to the chain that wraps every request made by the Client.
+// Middlewares run in the order they are registered, the first one being the outermost.
+func (b *builderImpl) Use(middlewares ...Middleware) {
+	b.middlewares = append(b.middlewares, middlewares...)
+}
+
+// SetAuthorization sets the authorization sent with every request made by the Client.
+// It can be overridden for a single request with WithAuthorization or an Authorization header.
+//
+//	Example:
+//		builder.SetAuthorization(go_requests.NewAuthorization().Basic("user", "password"))
+func (b *builderImpl) SetAuthorization(authorization Authorization) {
+	b.authorization = authorization
+}
+
+// SetTokenSource sets the OAuth2 token source used to authorize every request made by the Client.
+// Tokens are cached until shortly before they expire, and a request answered with 401 Unauthorized
+// is sent once more with a fresh token. The token source replaces the authorization set with SetAuthorization.
+//
+//	Example:
+//		builder.SetTokenSource(go_requests.NewClientCredentialsTokenSource(go_requests.OAuth2Config{
+//			TokenURL:     "https://auth.example.com/oauth/token",
+//			ClientID:     "id",
+//			ClientSecret: "secret",
+//		}))
+func (b *builderImpl) SetTokenSource(source TokenSource) {
+	b.tokenSource = source
+}
+
+// SetSigV4Signer sets the signer used to sign every request made by the Client with AWS Signature Version 4.
+// Requests are signed right before they are sent, after all the other middlewares, so every retry gets a fresh signature.
+//
+//	Example:
+//		builder.SetSigV4Signer(go_requests.NewSigV4Signer(accessKey, secretKey, "", "us-east-1", "s3"))
+func (b *builderImpl) SetSigV4Signer(signer SigV4Signer) {
+	b.sigV4Signer = signer
+}
+
+// SetBaseURL sets the URL that the relative URLs of the requests made by the Client are resolved against.
+// The path of a request is appended to the path of the base URL, whether it starts with a slash or not,
+// and its query is appended to the query of the base URL. Absolute URLs are sent as they are.
+//
+//	Example:
+//		builder.SetBaseURL("https://petstore.swagger.io/v2")
+//		client := builder.Build()
+//		// GET https://petstore.swagger.io/v2/pet/findByTags
+//		response, err := client.Get("/pet/findByTags")
+func (b *builderImpl) SetBaseURL(baseURL string) {
+	b.baseURL = baseURL
+}
+
+// SetCookieJar sets the jar that stores the cookies received by the Client and sends them with its requests.
+// Without a jar, which is the default, cookies are not kept between requests. The jar is also set on a copy of
+// the http client set with SetHTTPClient, which is left untouched.
+//
+//	Example:
+//		builder.SetCookieJar(go_requests.NewCookieJar(nil))
+func (b *builderImpl) SetCookieJar(jar http.CookieJar) {
+	b.cookieJar = jar
+}
+
+// SetRedirectPolicy sets the policy that decides which redirects the Client follows.
+// Without a policy, which is the default, up to 10 redirects are followed as net/http does.
+// The policy is also set on a copy of the http client set with SetHTTPClient, which is left untouched.
+//
+//	Example:
+//		builder.SetRedirectPolicy(go_requests.NewRedirectPolicy().SetFollow(false))
+func (b *builderImpl) SetRedirectPolicy(policy RedirectPolicy) {
+	b.redirectPolicy = policy
+}
+
+// SetCache sets the storage of the HTTP cache that answers the GET requests made by the Client when it can.
+// The cache follows RFC 9111 as a private cache: responses are stored according to their Cache-Control,
+// Expires, Age and Vary headers, and stale responses are revalidated with their ETag or Last-Modified headers.
+// Responses to other methods invalidate the stored response of their URL. Since the requests of a Client may carry
+// the credentials of different users, the responses to requests with an authorization are only stored when they are
+// public, s-maxage or must-revalidate. A nil storage disables the cache, which is the default.
+//
+//	Example:
+//		builder.SetCache(go_requests.NewMemoryCache(500))
+func (b *builderImpl) SetCache(storage CacheStorage) {
+	b.cacheStorage = storage
+}
+
+// SetRateLimiter sets the limiter that throttles the requests made by the Client.
+// Every attempt of a request takes a token, and so does every hedge sent by a HedgePolicy, while the responses
+// answered by the cache do not.
+// A nil limiter, which is the default, does not throttle the requests.
+//
+//	Example:
+//		builder.SetRateLimiter(go_requests.NewRateLimiter().SetHostLimit(10, 5).SetAdaptive(true))
+func (b *builderImpl) SetRateLimiter(limiter RateLimiter) {
+	b.rateLimiter = limiter
+}
+
+// SetCircuitBreaker sets the circuit breaker that stops the Client from sending requests to failing hosts.
+// The requests to a host whose circuit is open fail with an *ErrCircuitOpen error without being sent,
+// while the responses answered by the cache are still returned. A nil breaker, which is the default, disables it.
+//
+//	Example:
+//		builder.SetCircuitBreaker(go_requests.NewCircuitBreaker().SetFailureRatio(0.5, 20))
+func (b *builderImpl) SetCircuitBreaker(breaker CircuitBreaker) {
+	b.circuitBreaker = breaker
+}
+
+// SetHedgePolicy sets the policy that hedges the safe requests made by the Client: when a request has not been
+// answered within the delay of the policy, an identical one is sent and the first response wins.
+// It can be overridden for a single request with Request.SetHedgePolicy. A nil policy, which is the default,
+// disables hedging.
+// Every hedge takes a token from the RateLimiter of the Client before it is sent, and is not sent if the limiter
+// fails fast. The CircuitBreaker records a single outcome for a hedged request, the one of the winning response.
+//
+//	Example:
+//		builder.SetHedgePolicy(go_requests.NewHedgePolicy(100 * time.Millisecond))
+func (b *builderImpl) SetHedgePolicy(policy HedgePolicy) {
+	b.hedgePolicy = policy
+}
+
 // Build returns a Client that is used to make HTTP requests.
 // The Client is used to make HTTP requests.
 func (b *builderImpl) Build() Client {
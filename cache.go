@@ -0,0 +1,402 @@
+package go_requests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// heuristicFreshnessFraction is the fraction of the time since Last-Modified that a response without explicit
+	// freshness is fresh for (RFC 9111 section 4.2.2)
+	heuristicFreshnessFraction = 10
+	// maxHeuristicFreshness is the upper bound of the heuristic freshness lifetime
+	maxHeuristicFreshness = 24 * time.Hour
+	// maxCacheControlSeconds is the largest number of seconds of a directive, larger values are capped to it
+	// (RFC 9111 section 1.2.2)
+	maxCacheControlSeconds = 1<<31 - 1
+)
+
+// heuristicallyCacheable are the status codes that can be cached without explicit freshness (RFC 9110 section 15.1)
+var heuristicallyCacheable = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusPermanentRedirect:    true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// cacheEntry is a response stored in a CacheStorage
+type cacheEntry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	// Vary holds the values of the request headers named by the Vary header of the response
+	Vary http.Header `json:"vary,omitempty"`
+	// ResponseTime is when the response was received
+	ResponseTime time.Time `json:"response_time"`
+	// InitialAge is the corrected initial age of the response when it was received (RFC 9111 section 4.2.3)
+	InitialAge time.Duration `json:"initial_age"`
+}
+
+// httpCache is a private HTTP cache (RFC 9111) that answers GET requests from a CacheStorage
+type httpCache struct {
+	storage CacheStorage
+	// now returns the current time, it is replaced in tests
+	now func() time.Time
+}
+
+// cacheMiddleware returns the middleware that answers the requests from the storage when it can, and stores
+// the cacheable responses.
+func cacheMiddleware(storage CacheStorage) Middleware {
+	cache := &httpCache{storage: storage, now: time.Now}
+	return cache.middleware
+}
+
+// middleware is the Middleware of the cache.
+func (c *httpCache) middleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*Response, error) {
+		if isStream(req.Context()) {
+			return next(req)
+		}
+		if req.Method != http.MethodGet {
+			response, err := next(req)
+			// unsafe methods invalidate the stored response of their URL (RFC 9111 section 4.4)
+			if err == nil && !isSafeMethod(req.Method) && response.statusCode < http.StatusBadRequest {
+				c.storage.Delete(cacheKey(req))
+			}
+			return response, err
+		}
+		requestControl := parseCacheControl(req.Header.Values("Cache-Control"))
+		if _, ok := requestControl["no-store"]; ok || hasConditionalHeaders(req) {
+			return next(req)
+		}
+		key := cacheKey(req)
+		entry := c.load(key, req)
+		if entry != nil && c.usable(entry, requestControl) {
+			return c.response(entry, false), nil
+		}
+		if _, ok := requestControl["only-if-cached"]; ok {
+			return NewResponse(http.StatusGatewayTimeout, nil, nil), nil
+		}
+		requestTime := c.now()
+		if entry != nil {
+			req = conditionalRequest(req, entry)
+		}
+		response, err := next(req)
+		if err != nil {
+			return nil, err
+		}
+		responseTime := c.now()
+		if entry != nil && response.statusCode == http.StatusNotModified {
+			entry.update(response.header, requestTime, responseTime)
+			c.store(key, entry)
+			return c.response(entry, true), nil
+		}
+		if stored := newCacheEntry(req, response, requestTime, responseTime); stored != nil {
+			c.store(key, stored)
+		} else if entry != nil {
+			c.storage.Delete(key)
+		}
+		return response, nil
+	}
+}
+
+// load returns the entry stored for the request, or nil if there is none or if its Vary headers do not match.
+func (c *httpCache) load(key string, req *http.Request) *cacheEntry {
+	data, ok := c.storage.Get(key)
+	if !ok {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		c.storage.Delete(key)
+		return nil
+	}
+	for name, values := range entry.Vary {
+		if strings.Join(req.Header.Values(name), ",") != strings.Join(values, ",") {
+			return nil
+		}
+	}
+	return &entry
+}
+
+// store saves the entry under key.
+func (c *httpCache) store(key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	c.storage.Set(key, data)
+}
+
+// usable returns true if the entry can answer a request with the given Cache-Control directives without being
+// revalidated.
+func (c *httpCache) usable(entry *cacheEntry, requestControl map[string]string) bool {
+	if _, ok := requestControl["no-cache"]; ok {
+		return false
+	}
+	responseControl := parseCacheControl(entry.Header.Values("Cache-Control"))
+	if _, ok := responseControl["no-cache"]; ok {
+		return false
+	}
+	age := entry.currentAge(c.now())
+	lifetime := entry.freshnessLifetime()
+	if maxAge, ok := cacheControlSeconds(requestControl, "max-age"); ok && age > maxAge {
+		return false
+	}
+	if minFresh, ok := cacheControlSeconds(requestControl, "min-fresh"); ok {
+		age += minFresh
+	}
+	if age < lifetime {
+		return true
+	}
+	if _, ok := responseControl["must-revalidate"]; ok {
+		return false
+	}
+	if value, ok := requestControl["max-stale"]; ok {
+		if value == "" {
+			return true
+		}
+		maxStale, _ := cacheControlSeconds(requestControl, "max-stale")
+		return age-lifetime <= maxStale
+	}
+	return false
+}
+
+// response returns the Response of the entry, with its current age.
+func (c *httpCache) response(entry *cacheEntry, revalidated bool) *Response {
+	header := entry.Header.Clone()
+	header.Set("Age", strconv.FormatInt(int64(entry.currentAge(c.now())/time.Second), 10))
+	response := &Response{
+		statusCode:  entry.StatusCode,
+		status:      entry.Status,
+		header:      header,
+		body:        append([]byte(nil), entry.Body...),
+		contentType: header.Get("Content-Type"),
+		attempts:    1,
+		fromCache:   true,
+		revalidated: revalidated,
+	}
+	response.url, _ = url.Parse(entry.URL)
+	return response
+}
+
+// newCacheEntry returns the entry of a response, or nil if the response cannot be stored.
+func newCacheEntry(req *http.Request, response *Response, requestTime, responseTime time.Time) *cacheEntry {
+	responseControl := parseCacheControl(response.header.Values("Cache-Control"))
+	if _, ok := responseControl["no-store"]; ok {
+		return nil
+	}
+	// the requests of a client may carry the credentials of different users, so the responses to authorized
+	// requests are only stored when they explicitly allow it (RFC 9111 section 3.5)
+	if isAuthorized(req) && !allowsAuthorizedStorage(responseControl) {
+		return nil
+	}
+	vary := make(http.Header)
+	for _, value := range response.header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return nil
+			}
+			if name != "" {
+				vary[http.CanonicalHeaderKey(name)] = req.Header.Values(name)
+			}
+		}
+	}
+	entry := &cacheEntry{
+		URL:          req.URL.String(),
+		StatusCode:   response.statusCode,
+		Status:       response.status,
+		Header:       response.header.Clone(),
+		Body:         response.body,
+		ResponseTime: responseTime,
+	}
+	if len(vary) > 0 {
+		entry.Vary = vary
+	}
+	entry.InitialAge = initialAge(entry.Header, requestTime, responseTime)
+	_, hasMaxAge := responseControl["max-age"]
+	explicit := hasMaxAge || entry.Header.Get("Expires") != ""
+	validators := entry.Header.Get("ETag") != "" || entry.Header.Get("Last-Modified") != ""
+	if !explicit && !validators {
+		return nil
+	}
+	if !explicit && !heuristicallyCacheable[response.statusCode] {
+		return nil
+	}
+	return entry
+}
+
+// update refreshes the entry with the headers of a 304 Not Modified response (RFC 9111 section 4.3.4).
+func (e *cacheEntry) update(header http.Header, requestTime, responseTime time.Time) {
+	for name, values := range header {
+		switch name {
+		case "Content-Length", "Content-Encoding", "Transfer-Encoding":
+			continue
+		}
+		e.Header[name] = append([]string(nil), values...)
+	}
+	e.ResponseTime = responseTime
+	e.InitialAge = initialAge(e.Header, requestTime, responseTime)
+}
+
+// currentAge returns the age of the entry at now (RFC 9111 section 4.2.3).
+func (e *cacheEntry) currentAge(now time.Time) time.Duration {
+	resident := now.Sub(e.ResponseTime)
+	if resident < 0 {
+		resident = 0
+	}
+	return e.InitialAge + resident
+}
+
+// freshnessLifetime returns how long the entry is fresh for (RFC 9111 section 4.2.1).
+// The s-maxage directive is ignored, as it only applies to shared caches.
+func (e *cacheEntry) freshnessLifetime() time.Duration {
+	if maxAge, ok := cacheControlSeconds(parseCacheControl(e.Header.Values("Cache-Control")), "max-age"); ok {
+		return maxAge
+	}
+	date := e.date()
+	if value := e.Header.Get("Expires"); value != "" {
+		// an invalid Expires, such as "0", means already expired
+		expires, err := http.ParseTime(value)
+		if err != nil {
+			return 0
+		}
+		return expires.Sub(date)
+	}
+	if lastModified, err := http.ParseTime(e.Header.Get("Last-Modified")); err == nil && lastModified.Before(date) {
+		lifetime := date.Sub(lastModified) / heuristicFreshnessFraction
+		if lifetime > maxHeuristicFreshness {
+			lifetime = maxHeuristicFreshness
+		}
+		return lifetime
+	}
+	return 0
+}
+
+// date returns the Date of the entry, or the time it was received without one.
+func (e *cacheEntry) date() time.Time {
+	if date, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+		return date
+	}
+	return e.ResponseTime
+}
+
+// initialAge returns the corrected initial age of a response (RFC 9111 section 4.2.3).
+func initialAge(header http.Header, requestTime, responseTime time.Time) time.Duration {
+	apparentAge := time.Duration(0)
+	if date, err := http.ParseTime(header.Get("Date")); err == nil && responseTime.After(date) {
+		apparentAge = responseTime.Sub(date)
+	}
+	age := time.Duration(0)
+	if seconds, err := strconv.ParseInt(strings.TrimSpace(header.Get("Age")), 10, 64); err == nil && seconds > 0 {
+		age = time.Duration(seconds) * time.Second
+	}
+	correctedAge := age + responseTime.Sub(requestTime)
+	if apparentAge > correctedAge {
+		return apparentAge
+	}
+	return correctedAge
+}
+
+// conditionalRequest returns a copy of the request that is only answered with a body if the entry is outdated.
+func conditionalRequest(req *http.Request, entry *cacheEntry) *http.Request {
+	etag, lastModified := entry.Header.Get("ETag"), entry.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return req
+	}
+	req = req.Clone(req.Context())
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+	return req
+}
+
+// hasConditionalHeaders returns true if the request has conditional headers set by the caller, whose response
+// must be left to the caller.
+func hasConditionalHeaders(req *http.Request) bool {
+	for _, name := range []string{"If-None-Match", "If-Modified-Since", "If-Match", "If-Unmodified-Since", "If-Range", "Range"} {
+		if req.Header.Get(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isSafeMethod returns true for the methods that do not change the state of the server.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// isAuthorized returns true if the request carries credentials, with an Authorization header or an authorization
+// of its context that a middleware answers a challenge with.
+func isAuthorized(req *http.Request) bool {
+	return req.Header.Get("Authorization") != "" || authorizationFromContext(req.Context()) != nil
+}
+
+// allowsAuthorizedStorage returns true if the Cache-Control directives of a response to an authorized request
+// allow a cache shared by several users to store it.
+func allowsAuthorizedStorage(responseControl map[string]string) bool {
+	for _, directive := range []string{"public", "s-maxage", "must-revalidate"} {
+		if _, ok := responseControl[directive]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheKey returns the key of the stored response of the URL of the request.
+func cacheKey(req *http.Request) string {
+	return req.URL.String()
+}
+
+// parseCacheControl parses Cache-Control headers into their lower case directives and unquoted values.
+func parseCacheControl(values []string) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range values {
+		for _, directive := range strings.Split(value, ",") {
+			name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name == "" {
+				continue
+			}
+			directives[strings.ToLower(name)] = strings.Trim(argument, `"`)
+		}
+	}
+	return directives
+}
+
+// cacheControlSeconds returns the value of a directive that is a number of seconds, e.g. max-age=60.
+func cacheControlSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	value, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, true
+	}
+	if seconds > maxCacheControlSeconds {
+		seconds = maxCacheControlSeconds
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,273 @@
+package go_requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_goHTTPClient_cache(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/etag":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/last-modified":
+			w.Header().Set("Cache-Control", "max-age=0")
+			w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+			if r.Header.Get("If-Modified-Since") != "" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/private":
+			w.Header().Set("Cache-Control", "max-age=60")
+			_, _ = w.Write([]byte("secret of " + r.Header.Get("Authorization")))
+			return
+		case "/public":
+			w.Header().Set("Cache-Control", "public, max-age=60")
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store")
+		case "/vary":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "Accept")
+			_, _ = w.Write([]byte(r.Header.Get("Accept")))
+			return
+		}
+		_, _ = w.Write([]byte("body of " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		path            string
+		headers         []http.Header
+		wantHits        int32
+		wantFromCache   bool
+		wantRevalidated bool
+		wantBody        string
+	}{
+		{name: "fresh", path: "/fresh", wantHits: 1, wantFromCache: true, wantBody: "body of /fresh"},
+		{name: "etag", path: "/etag", wantHits: 2, wantFromCache: true, wantRevalidated: true, wantBody: "body of /etag"},
+		{name: "last modified", path: "/last-modified", wantHits: 2, wantFromCache: true, wantRevalidated: true, wantBody: "body of /last-modified"},
+		{name: "no store", path: "/no-store", wantHits: 2, wantBody: "body of /no-store"},
+		{
+			name:     "vary",
+			path:     "/vary",
+			headers:  []http.Header{{"Accept": {"application/json"}}, {"Accept": {"application/xml"}}},
+			wantHits: 2,
+			wantBody: "application/xml",
+		},
+		{
+			name:     "authorization",
+			path:     "/private",
+			headers:  []http.Header{{"Authorization": {"Bearer alice"}}, {"Authorization": {"Bearer bob"}}},
+			wantHits: 2,
+			wantBody: "secret of Bearer bob",
+		},
+		{
+			name:          "authorization public",
+			path:          "/public",
+			headers:       []http.Header{{"Authorization": {"Bearer alice"}}, {"Authorization": {"Bearer bob"}}},
+			wantHits:      1,
+			wantFromCache: true,
+			wantBody:      "body of /public",
+		},
+		{
+			name:            "request no-cache",
+			path:            "/fresh",
+			headers:         []http.Header{nil, {"Cache-Control": {"no-cache"}}},
+			wantHits:        2,
+			wantFromCache:   false,
+			wantRevalidated: false,
+			wantBody:        "body of /fresh",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&hits, 0)
+			builder := NewBuilder()
+			builder.SetCache(NewMemoryCache(10))
+			client := builder.Build()
+			headers := tt.headers
+			if headers == nil {
+				headers = []http.Header{nil, nil}
+			}
+			var response *Response
+			for i, header := range headers {
+				var err error
+				if response, err = client.Get(server.URL+tt.path, header); err != nil {
+					t.Fatal(err)
+				}
+				if i == 0 && response.FromCache() {
+					t.Error("first response FromCache() = true, want false")
+				}
+			}
+			if got := atomic.LoadInt32(&hits); got != tt.wantHits {
+				t.Errorf("server hits = %d, want %d", got, tt.wantHits)
+			}
+			if response.FromCache() != tt.wantFromCache || response.Revalidated() != tt.wantRevalidated {
+				t.Errorf("FromCache(), Revalidated() = %v, %v, want %v, %v",
+					response.FromCache(), response.Revalidated(), tt.wantFromCache, tt.wantRevalidated)
+			}
+			if response.StatusCode() != http.StatusOK || response.String() != tt.wantBody {
+				t.Errorf("response = %d %q, want 200 %q", response.StatusCode(), response.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_goHTTPClient_cache_invalidation(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&hits, 1)
+			w.Header().Set("Cache-Control", "max-age=60")
+		}
+	}))
+	defer server.Close()
+	builder := NewBuilder()
+	builder.SetCache(NewMemoryCache(10))
+	client := builder.Build()
+	for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodPut, http.MethodGet} {
+		if _, err := client.Do(NewRequest(Method(method), server.URL+"/pet/1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("server hits = %d, want 2", got)
+	}
+}
+
+func Test_httpCache_staleness(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := &httpCache{storage: NewMemoryCache(1), now: func() time.Time { return now }}
+	var sent int
+	roundTrip := cache.middleware(func(req *http.Request) (*Response, error) {
+		sent++
+		header := http.Header{
+			"Cache-Control": {"max-age=60"},
+			"Date":          {now.Add(-10 * time.Second).Format(http.TimeFormat)},
+		}
+		return NewResponse(http.StatusOK, header, []byte("pets")), nil
+	})
+	get := func(headers http.Header) *Response {
+		req := httptest.NewRequest(http.MethodGet, "https://api.example.com/pets", nil)
+		for key, values := range headers {
+			req.Header[key] = values
+		}
+		response, err := roundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+	get(nil)
+	now = now.Add(30 * time.Second)
+	if response := get(nil); !response.FromCache() || response.Header().Get("Age") != "40" {
+		t.Errorf("FromCache(), Age = %v, %v, want true, 40", response.FromCache(), response.Header().Get("Age"))
+	}
+	if response := get(http.Header{"Cache-Control": {"max-age=30"}}); response.FromCache() {
+		t.Error("FromCache() = true for a max-age older than the response, want false")
+	}
+	now = now.Add(60 * time.Second)
+	if response := get(http.Header{"Cache-Control": {"max-stale"}}); !response.FromCache() {
+		t.Error("FromCache() = false with max-stale, want true")
+	}
+	if response := get(nil); response.FromCache() {
+		t.Error("FromCache() = true for a stale response, want false")
+	}
+	if sent != 3 {
+		t.Errorf("requests sent = %d, want 3", sent)
+	}
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/other", nil)
+	req.Header.Set("Cache-Control", "only-if-cached")
+	if response, _ := roundTrip(req); response.StatusCode() != http.StatusGatewayTimeout {
+		t.Errorf("StatusCode() = %d for only-if-cached, want 504", response.StatusCode())
+	}
+}
+
+func Test_cacheEntry_freshnessLifetime(t *testing.T) {
+	date := time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{name: "max-age", header: http.Header{"Cache-Control": {"public, max-age=120"}, "Expires": {date.Add(time.Hour).Format(http.TimeFormat)}}, want: 2 * time.Minute},
+		{name: "expires", header: http.Header{"Expires": {date.Add(time.Hour).Format(http.TimeFormat)}}, want: time.Hour},
+		{name: "invalid expires", header: http.Header{"Expires": {"0"}}, want: 0},
+		{name: "heuristic", header: http.Header{"Last-Modified": {date.Add(-10 * time.Hour).Format(http.TimeFormat)}}, want: time.Hour},
+		{name: "heuristic bound", header: http.Header{"Last-Modified": {date.Add(-1000 * time.Hour).Format(http.TimeFormat)}}, want: 24 * time.Hour},
+		{name: "none", header: http.Header{}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.header.Set("Date", date.Format(http.TimeFormat))
+			entry := &cacheEntry{Header: tt.header, ResponseTime: date}
+			if got := entry.freshnessLifetime(); got != tt.want {
+				t.Errorf("freshnessLifetime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_initialAge(t *testing.T) {
+	requestTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	responseTime := requestTime.Add(2 * time.Second)
+	header := http.Header{"Age": {"30"}, "Date": {requestTime.Add(-10 * time.Second).Format(http.TimeFormat)}}
+	if got := initialAge(header, requestTime, responseTime); got != 32*time.Second {
+		t.Errorf("initialAge() = %v, want 32s", got)
+	}
+	header.Set("Age", "5")
+	if got := initialAge(header, requestTime, responseTime); got != 12*time.Second {
+		t.Errorf("initialAge() = %v, want 12s", got)
+	}
+}
+
+func Test_memoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", []byte("1"))
+	cache.Set("b", []byte("2"))
+	cache.Get("a")
+	cache.Set("c", []byte("3"))
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Get(b) found, want the least recently used entry evicted")
+	}
+	if data, ok := cache.Get("a"); !ok || string(data) != "1" {
+		t.Errorf("Get(a) = %q, %v, want 1, true", data, ok)
+	}
+	cache.Delete("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Get(a) found after Delete")
+	}
+}
+
+func Test_diskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set("https://api.example.com/pets?page=1", []byte("pets"))
+	reopened, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, ok := reopened.Get("https://api.example.com/pets?page=1"); !ok || string(data) != "pets" {
+		t.Errorf("Get() = %q, %v, want pets, true", data, ok)
+	}
+	reopened.Delete("https://api.example.com/pets?page=1")
+	if _, ok := cache.Get("https://api.example.com/pets?page=1"); ok {
+		t.Error("Get() found after Delete")
+	}
+}
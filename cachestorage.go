@@ -0,0 +1,152 @@
+package go_requests
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// defaultMemoryCacheEntries is the default maximum number of responses of a memory cache
+const defaultMemoryCacheEntries = 1000
+
+// CacheStorage stores the responses of the HTTP cache of a Client, see Builder.SetCache.
+// The responses are opaque byte slices stored by key. A CacheStorage must be safe for concurrent use.
+type CacheStorage interface {
+	// Get returns the data stored under key, if any.
+	Get(key string) ([]byte, bool)
+	// Set stores data under key, replacing the data already stored.
+	Set(key string, data []byte)
+	// Delete removes the data stored under key.
+	Delete(key string)
+}
+
+// memoryCache is a CacheStorage that keeps the most recently used responses in memory
+type memoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	// entries holds the *memoryCacheEntry values, the most recently used first
+	entries *list.List
+	keys    map[string]*list.Element
+}
+
+// memoryCacheEntry is an element of the list of a memoryCache
+type memoryCacheEntry struct {
+	key  string
+	data []byte
+}
+
+// NewMemoryCache returns a CacheStorage that keeps up to maxEntries responses in memory, evicting the least
+// recently used ones. If maxEntries is not positive, 1000 responses are kept.
+//
+//	Example:
+//		builder.SetCache(go_requests.NewMemoryCache(500))
+func NewMemoryCache(maxEntries int) CacheStorage {
+	if maxEntries <= 0 {
+		maxEntries = defaultMemoryCacheEntries
+	}
+	return &memoryCache{
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		keys:       make(map[string]*list.Element),
+	}
+}
+
+// Get returns the data stored under key and marks it as recently used.
+func (m *memoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	element, ok := m.keys[key]
+	if !ok {
+		return nil, false
+	}
+	m.entries.MoveToFront(element)
+	return element.Value.(*memoryCacheEntry).data, true
+}
+
+// Set stores data under key, evicting the least recently used data if the cache is full.
+func (m *memoryCache) Set(key string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if element, ok := m.keys[key]; ok {
+		element.Value.(*memoryCacheEntry).data = data
+		m.entries.MoveToFront(element)
+		return
+	}
+	m.keys[key] = m.entries.PushFront(&memoryCacheEntry{key: key, data: data})
+	for m.entries.Len() > m.maxEntries {
+		oldest := m.entries.Back()
+		m.entries.Remove(oldest)
+		delete(m.keys, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Delete removes the data stored under key.
+func (m *memoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if element, ok := m.keys[key]; ok {
+		m.entries.Remove(element)
+		delete(m.keys, key)
+	}
+}
+
+// diskCache is a CacheStorage that keeps every response in a file of a directory
+type diskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a CacheStorage that keeps the responses in files of dir, which is created if needed,
+// so that they survive between the runs of a program. Files that cannot be read or written are treated as missing.
+//
+//	Example:
+//		storage, err := go_requests.NewDiskCache(filepath.Join(os.TempDir(), "petstore-cache"))
+//		if err != nil {
+//			return err
+//		}
+//		builder.SetCache(storage)
+func NewDiskCache(dir string) (CacheStorage, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("disk cache: %w", err)
+	}
+	return &diskCache{dir: dir}, nil
+}
+
+// Get returns the data stored under key.
+func (d *diskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set stores data under key. The file is replaced atomically, so that a concurrent Get never reads a partial file.
+func (d *diskCache) Set(key string, data []byte) {
+	file, err := os.CreateTemp(d.dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err != nil || closeErr != nil {
+		return
+	}
+	_ = os.Rename(file.Name(), d.path(key))
+}
+
+// Delete removes the data stored under key.
+func (d *diskCache) Delete(key string) {
+	_ = os.Remove(d.path(key))
+}
+
+// path returns the path of the file of key, named after its hash so that any key is a valid file name.
+func (d *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,367 @@
+package go_requests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCircuitConsecutiveFailures is the default number of consecutive failures that opens a circuit
+	defaultCircuitConsecutiveFailures = 5
+	// defaultCircuitMinRequests is the default number of requests of a window before its failure ratio is checked
+	defaultCircuitMinRequests = 10
+	// defaultCircuitWindow is the default duration of the window over which the failure ratio is computed
+	defaultCircuitWindow = time.Minute
+	// defaultCircuitCoolDown is the default time a circuit stays open
+	defaultCircuitCoolDown = 30 * time.Second
+	// defaultCircuitHalfOpenRequests is the default number of probes of a half-open circuit
+	defaultCircuitHalfOpenRequests = 1
+)
+
+// CircuitState is the state of the circuit of a host.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through. It is the initial state.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request with ErrCircuitOpen until the cool-down ends.
+	CircuitOpen
+	// CircuitHalfOpen lets a few requests through to probe the host. The circuit closes if they succeed,
+	// and opens again if one of them fails.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreaker stops sending requests to a host that keeps failing, so that a dependency that is down is not
+// hammered until timeouts pile up. Every host, with its port, has a circuit of its own.
+// A CircuitBreaker is attached to a client with Builder.SetCircuitBreaker.
+//
+//	Example:
+//		builder.SetCircuitBreaker(go_requests.NewCircuitBreaker().
+//			SetConsecutiveFailures(3).
+//			SetCoolDown(10 * time.Second).
+//			OnStateChange(func(host string, from, to go_requests.CircuitState) {
+//				log.Printf("circuit of %s: %s -> %s", host, from, to)
+//			}))
+type CircuitBreaker interface {
+	// SetConsecutiveFailures sets the number of consecutive failures that opens a circuit. Zero disables this trigger.
+	SetConsecutiveFailures(failures int) CircuitBreaker
+	// SetFailureRatio sets the ratio (0 to 1) of failed requests that opens a circuit, once a window has at least
+	// minRequests requests. Zero disables this trigger, which is the default.
+	SetFailureRatio(ratio float64, minRequests int) CircuitBreaker
+	// SetWindow sets the duration of the window over which the failure ratio is computed.
+	SetWindow(window time.Duration) CircuitBreaker
+	// SetCoolDown sets how long a circuit stays open before it lets a request through to probe the host.
+	SetCoolDown(coolDown time.Duration) CircuitBreaker
+	// SetHalfOpenRequests sets how many probes a half-open circuit lets through, which must all succeed to close it.
+	SetHalfOpenRequests(requests int) CircuitBreaker
+	// SetFailureCondition replaces the function that decides whether the response or error of a request is a failure.
+	// By default, transport errors, timeouts and 5xx responses are failures. Canceled requests and the ones rejected
+	// by the RateLimiter are always ignored.
+	SetFailureCondition(isFailure func(response *Response, err error) bool) CircuitBreaker
+	// OnStateChange adds a function called whenever the circuit of a host changes state, e.g. for alerting.
+	// It is called synchronously by the request that caused the change.
+	OnStateChange(callback func(host string, from, to CircuitState)) CircuitBreaker
+	// State returns the state of the circuit of host.
+	State(host string) CircuitState
+	// Allow returns an *ErrCircuitOpen error if the circuit of host rejects a request. Otherwise, done must be
+	// called with the response or error of the request.
+	Allow(host string) (done func(response *Response, err error), err error)
+}
+
+// circuit is the circuit of a host
+type circuit struct {
+	state CircuitState
+	// generation changes with every state change, so that the requests of a previous state are ignored
+	generation          uint64
+	consecutiveFailures int
+	requests            int
+	failures            int
+	windowStart         time.Time
+	openedAt            time.Time
+	// probes is the number of probes let through by the half-open circuit, successes those that succeeded
+	probes    int
+	successes int
+}
+
+// circuitStateChange is a state change to report to the callbacks
+type circuitStateChange struct {
+	host     string
+	from, to CircuitState
+}
+
+// circuitBreakerImpl is the implementation of the CircuitBreaker interface
+type circuitBreakerImpl struct {
+	mu                  sync.Mutex
+	consecutiveFailures int
+	failureRatio        float64
+	minRequests         int
+	window              time.Duration
+	coolDown            time.Duration
+	halfOpenRequests    int
+	isFailure           func(response *Response, err error) bool
+	callbacks           []func(host string, from, to CircuitState)
+	circuits            map[string]*circuit
+	// now returns the current time, it is replaced in tests
+	now func() time.Time
+}
+
+// NewCircuitBreaker returns a new CircuitBreaker with the default values.
+//
+//   - a circuit opens after 5 consecutive failures
+//   - the failure ratio is disabled, its window is 1 minute
+//   - a circuit stays open for 30 seconds
+//   - a half-open circuit lets 1 probe through
+func NewCircuitBreaker() CircuitBreaker {
+	return &circuitBreakerImpl{
+		consecutiveFailures: defaultCircuitConsecutiveFailures,
+		minRequests:         defaultCircuitMinRequests,
+		window:              defaultCircuitWindow,
+		coolDown:            defaultCircuitCoolDown,
+		halfOpenRequests:    defaultCircuitHalfOpenRequests,
+		isFailure:           isCircuitFailure,
+		circuits:            make(map[string]*circuit),
+		now:                 time.Now,
+	}
+}
+
+// SetConsecutiveFailures sets the number of consecutive failures that opens a circuit.
+func (b *circuitBreakerImpl) SetConsecutiveFailures(failures int) CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if failures < 0 {
+		failures = 0
+	}
+	b.consecutiveFailures = failures
+	return b
+}
+
+// SetFailureRatio sets the ratio of failed requests that opens a circuit.
+func (b *circuitBreakerImpl) SetFailureRatio(ratio float64, minRequests int) CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if minRequests < 1 {
+		minRequests = 1
+	}
+	b.failureRatio = ratio
+	b.minRequests = minRequests
+	return b
+}
+
+// SetWindow sets the duration of the window over which the failure ratio is computed.
+func (b *circuitBreakerImpl) SetWindow(window time.Duration) CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.window = window
+	return b
+}
+
+// SetCoolDown sets how long a circuit stays open.
+func (b *circuitBreakerImpl) SetCoolDown(coolDown time.Duration) CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.coolDown = coolDown
+	return b
+}
+
+// SetHalfOpenRequests sets how many probes a half-open circuit lets through.
+func (b *circuitBreakerImpl) SetHalfOpenRequests(requests int) CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if requests < 1 {
+		requests = 1
+	}
+	b.halfOpenRequests = requests
+	return b
+}
+
+// SetFailureCondition replaces the function that decides whether a request failed.
+func (b *circuitBreakerImpl) SetFailureCondition(isFailure func(response *Response, err error) bool) CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if isFailure == nil {
+		isFailure = isCircuitFailure
+	}
+	b.isFailure = isFailure
+	return b
+}
+
+// OnStateChange adds a function called whenever the circuit of a host changes state.
+func (b *circuitBreakerImpl) OnStateChange(callback func(host string, from, to CircuitState)) CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.callbacks = append(b.callbacks, callback)
+	return b
+}
+
+// State returns the state of the circuit of host. An open circuit whose cool-down has ended is half-open.
+func (b *circuitBreakerImpl) State(host string) CircuitState {
+	b.mu.Lock()
+	c, ok := b.circuits[host]
+	if !ok {
+		b.mu.Unlock()
+		return CircuitClosed
+	}
+	change := b.checkCoolDown(host, c, b.now())
+	state := c.state
+	b.mu.Unlock()
+	b.notify(change)
+	return state
+}
+
+// Allow returns an error if the circuit of host rejects a request.
+func (b *circuitBreakerImpl) Allow(host string) (func(response *Response, err error), error) {
+	b.mu.Lock()
+	now := b.now()
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{windowStart: now}
+		b.circuits[host] = c
+	}
+	change := b.checkCoolDown(host, c, now)
+	switch c.state {
+	case CircuitOpen:
+		b.mu.Unlock()
+		b.notify(change)
+		return nil, &ErrCircuitOpen{Host: host, Until: c.openedAt.Add(b.coolDown)}
+	case CircuitHalfOpen:
+		if c.probes >= b.halfOpenRequests {
+			b.mu.Unlock()
+			b.notify(change)
+			return nil, &ErrCircuitOpen{Host: host, Until: now}
+		}
+		c.probes++
+	}
+	generation := c.generation
+	b.mu.Unlock()
+	b.notify(change)
+	return func(response *Response, err error) {
+		b.record(host, generation, response, err)
+	}, nil
+}
+
+// record updates the circuit of host with the outcome of a request allowed in the given generation.
+func (b *circuitBreakerImpl) record(host string, generation uint64, response *Response, err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrRateLimited) {
+		b.mu.Lock()
+		// a request that was canceled or not sent does not tell anything about the host, another probe may be sent
+		if c := b.circuits[host]; c.generation == generation && c.state == CircuitHalfOpen {
+			c.probes--
+		}
+		b.mu.Unlock()
+		return
+	}
+	b.mu.Lock()
+	isFailure := b.isFailure
+	b.mu.Unlock()
+	failed := isFailure(response, err)
+	b.mu.Lock()
+	c := b.circuits[host]
+	if c.generation != generation {
+		b.mu.Unlock()
+		return
+	}
+	now := b.now()
+	var change *circuitStateChange
+	switch c.state {
+	case CircuitHalfOpen:
+		if failed {
+			change = b.setState(host, c, CircuitOpen, now)
+			break
+		}
+		c.successes++
+		if c.successes >= b.halfOpenRequests {
+			change = b.setState(host, c, CircuitClosed, now)
+		}
+	case CircuitClosed:
+		if b.window > 0 && now.Sub(c.windowStart) >= b.window {
+			c.requests, c.failures, c.windowStart = 0, 0, now
+		}
+		c.requests++
+		if !failed {
+			c.consecutiveFailures = 0
+			break
+		}
+		c.failures++
+		c.consecutiveFailures++
+		if b.consecutiveFailures > 0 && c.consecutiveFailures >= b.consecutiveFailures ||
+			b.failureRatio > 0 && c.requests >= b.minRequests && float64(c.failures)/float64(c.requests) >= b.failureRatio {
+			change = b.setState(host, c, CircuitOpen, now)
+		}
+	}
+	b.mu.Unlock()
+	b.notify(change)
+}
+
+// checkCoolDown makes an open circuit half-open once its cool-down has ended. b.mu must be held.
+func (b *circuitBreakerImpl) checkCoolDown(host string, c *circuit, now time.Time) *circuitStateChange {
+	if c.state == CircuitOpen && !now.Before(c.openedAt.Add(b.coolDown)) {
+		return b.setState(host, c, CircuitHalfOpen, now)
+	}
+	return nil
+}
+
+// setState changes the state of the circuit and resets its counters. b.mu must be held.
+func (b *circuitBreakerImpl) setState(host string, c *circuit, state CircuitState, now time.Time) *circuitStateChange {
+	change := &circuitStateChange{host: host, from: c.state, to: state}
+	c.state = state
+	c.generation++
+	c.consecutiveFailures, c.requests, c.failures, c.windowStart = 0, 0, 0, now
+	c.probes, c.successes = 0, 0
+	if state == CircuitOpen {
+		c.openedAt = now
+	}
+	return change
+}
+
+// notify calls the callbacks with the state change, if any. b.mu must not be held.
+func (b *circuitBreakerImpl) notify(change *circuitStateChange) {
+	if change == nil {
+		return
+	}
+	b.mu.Lock()
+	callbacks := b.callbacks
+	b.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(change.host, change.from, change.to)
+	}
+}
+
+// isCircuitFailure is the default failure condition: transport errors, timeouts and 5xx responses.
+func isCircuitFailure(response *Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return response != nil && response.StatusCode() >= http.StatusInternalServerError
+}
+
+// circuitBreakerMiddleware returns the middleware that rejects the requests to the hosts whose circuit is open,
+// and records the outcome of the others.
+func circuitBreakerMiddleware(breaker CircuitBreaker) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			done, err := breaker.Allow(req.URL.Host)
+			if err != nil {
+				return nil, err
+			}
+			response, err := next(req)
+			done(response, err)
+			return response, err
+		}
+	}
+}
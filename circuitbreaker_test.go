@@ -0,0 +1,158 @@
+package go_requests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// circuitTestBreaker returns a breaker whose clock is advanced by the returned function, and the state changes
+// it reports
+func circuitTestBreaker() (*circuitBreakerImpl, func(time.Duration), *[]string) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker().(*circuitBreakerImpl)
+	breaker.now = func() time.Time { return now }
+	var changes []string
+	breaker.OnStateChange(func(host string, from, to CircuitState) {
+		changes = append(changes, host+": "+from.String()+" -> "+to.String())
+	})
+	return breaker, func(d time.Duration) { now = now.Add(d) }, &changes
+}
+
+// send records a request with the given status code, or a transport error if it is 0
+func sendThroughBreaker(t *testing.T, b *circuitBreakerImpl, host string, statusCode int) error {
+	t.Helper()
+	done, err := b.Allow(host)
+	if err != nil {
+		return err
+	}
+	if statusCode == 0 {
+		done(nil, errors.New("connection refused"))
+	} else {
+		done(NewResponse(statusCode, nil, nil), nil)
+	}
+	return nil
+}
+
+func Test_circuitBreakerImpl_consecutiveFailures(t *testing.T) {
+	breaker, advance, changes := circuitTestBreaker()
+	breaker.SetConsecutiveFailures(3).SetCoolDown(10 * time.Second)
+	for _, status := range []int{500, 0, 200, 500, 502} {
+		if err := sendThroughBreaker(t, breaker, "api.example.com", status); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := breaker.State("api.example.com"); got != CircuitClosed {
+		t.Fatalf("State() = %v, want closed after a success reset the failures", got)
+	}
+	if err := sendThroughBreaker(t, breaker, "api.example.com", 0); err != nil {
+		t.Fatal(err)
+	}
+	err := sendThroughBreaker(t, breaker, "api.example.com", 200)
+	var open *ErrCircuitOpen
+	if !errors.As(err, &open) || open.Host != "api.example.com" || open.Until != breaker.now().Add(10*time.Second) {
+		t.Fatalf("send() error = %v, want *ErrCircuitOpen for api.example.com", err)
+	}
+	if got := breaker.State("other.example.com"); got != CircuitClosed {
+		t.Errorf("State(other) = %v, want closed", got)
+	}
+
+	advance(10 * time.Second)
+	// a single probe is let through
+	done, err := breaker.Allow("api.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = breaker.Allow("api.example.com"); !errors.As(err, &open) {
+		t.Errorf("second probe error = %v, want *ErrCircuitOpen", err)
+	}
+	done(NewResponse(http.StatusServiceUnavailable, nil, nil), nil)
+	if got := breaker.State("api.example.com"); got != CircuitOpen {
+		t.Fatalf("State() = %v, want open after a failed probe", got)
+	}
+	advance(10 * time.Second)
+	if err = sendThroughBreaker(t, breaker, "api.example.com", 200); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"api.example.com: closed -> open",
+		"api.example.com: open -> half-open",
+		"api.example.com: half-open -> open",
+		"api.example.com: open -> half-open",
+		"api.example.com: half-open -> closed",
+	}
+	if !reflect.DeepEqual(*changes, want) {
+		t.Errorf("state changes = %v, want %v", *changes, want)
+	}
+}
+
+func Test_circuitBreakerImpl_failureRatio(t *testing.T) {
+	breaker, advance, _ := circuitTestBreaker()
+	breaker.SetConsecutiveFailures(0).SetFailureRatio(0.5, 4).SetWindow(time.Minute)
+	for _, status := range []int{500, 200, 500} {
+		_ = sendThroughBreaker(t, breaker, "api.example.com", status)
+	}
+	// the window ends before the fourth request, which starts a new one
+	advance(time.Minute)
+	for _, status := range []int{500, 200, 200} {
+		_ = sendThroughBreaker(t, breaker, "api.example.com", status)
+	}
+	if got := breaker.State("api.example.com"); got != CircuitClosed {
+		t.Fatalf("State() = %v, want closed", got)
+	}
+	_ = sendThroughBreaker(t, breaker, "api.example.com", 500)
+	if got := breaker.State("api.example.com"); got != CircuitOpen {
+		t.Errorf("State() = %v, want open at a failure ratio of 0.5", got)
+	}
+}
+
+func Test_circuitBreakerImpl_ignored(t *testing.T) {
+	breaker, _, _ := circuitTestBreaker()
+	breaker.SetConsecutiveFailures(1).SetFailureCondition(func(response *Response, err error) bool {
+		return err != nil || response.StatusCode() == http.StatusTooManyRequests
+	})
+	for _, err := range []error{context.Canceled, ErrRateLimited} {
+		done, _ := breaker.Allow("api.example.com")
+		done(nil, err)
+	}
+	_ = sendThroughBreaker(t, breaker, "api.example.com", http.StatusInternalServerError)
+	if got := breaker.State("api.example.com"); got != CircuitClosed {
+		t.Fatalf("State() = %v, want closed", got)
+	}
+	_ = sendThroughBreaker(t, breaker, "api.example.com", http.StatusTooManyRequests)
+	if got := breaker.State("api.example.com"); got != CircuitOpen {
+		t.Errorf("State() = %v, want open with the custom failure condition", got)
+	}
+}
+
+func Test_goHTTPClient_circuitBreaker(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	builder := NewBuilder()
+	builder.SetCircuitBreaker(NewCircuitBreaker().SetConsecutiveFailures(2))
+	client := builder.Build()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(server.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := client.Get(server.URL)
+	var open *ErrCircuitOpen
+	serverURL, _ := url.Parse(server.URL)
+	if !errors.As(err, &open) || open.Host != serverURL.Host {
+		t.Errorf("Get() error = %v, want *ErrCircuitOpen for %s", err, serverURL.Host)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("server hits = %d, want 2", got)
+	}
+}
@@ -1,8 +1,12 @@
 package go_requests
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"sync"
 )
 
@@ -11,19 +15,40 @@ type Method string
 // goHTTPClient is the default implementation of the Client interface
 // it is used to make http requests
 type goHTTPClient struct {
-	builder     *builderImpl
-	client      *http.Client
-	clientOnce  sync.Once
+	builder    *builderImpl
+	client     *http.Client
+	clientOnce sync.Once
+	// mu guards queryParams
+	mu          sync.Mutex
 	queryParams QueryParams
+	digest      digestAuth
 }
 
+// QueryParams returns the query params added to the next request sent by the client, after which they are reset.
+// They are shared by every goroutine that uses the client; set the query params of a Request sent with Do instead
+// when the client is used concurrently.
 func (c *goHTTPClient) QueryParams() QueryParams {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.queryParams == nil {
 		c.queryParams = NewQueryParams()
 	}
 	return c.queryParams
 }
 
+// takeQueryParams returns the query params set on the client for the next request and resets them.
+// The array style of the client query params is kept.
+func (c *goHTTPClient) takeQueryParams() QueryParams {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.queryParams == nil || c.queryParams.Len() == 0 {
+		return nil
+	}
+	params := c.queryParams
+	c.queryParams = NewQueryParams().SetArrayStyle(params.ArrayStyle())
+	return params
+}
+
 // Client is an interface for http client
 type Client interface {
 	QueryParams() QueryParams
@@ -37,10 +62,67 @@ type Client interface {
 	Patch(url string, body []byte, headers ...http.Header) (*Response, error)
 	Delete(url string, body []byte, headers ...http.Header) (*Response, error)
 	Head(url string, body []byte, headers ...http.Header) (*Response, error)
+
+	GetWithContext(ctx context.Context, url string, headers ...http.Header) (*Response, error)
+	PostWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error)
+	PutWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error)
+	PatchWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error)
+	DeleteWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error)
+	HeadWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error)
+
+	Stream(ctx context.Context, method Method, url string, body []byte, headers ...http.Header) (*Response, error)
+	Upload(ctx context.Context, method Method, url string, body io.Reader, contentLength int64, headers ...http.Header) (*Response, error)
+	PostMultipart(ctx context.Context, url string, body Multipart, headers ...http.Header) (*Response, error)
+	PutMultipart(ctx context.Context, url string, body Multipart, headers ...http.Header) (*Response, error)
+	PostValue(url string, v interface{}, headers ...http.Header) (*Response, error)
+	PutValue(url string, v interface{}, headers ...http.Header) (*Response, error)
+	PatchValue(url string, v interface{}, headers ...http.Header) (*Response, error)
+
+	Do(req Request) (*Response, error)
+	DoWithContext(ctx context.Context, req Request) (*Response, error)
+
+	Cookies(url string) ([]*http.Cookie, error)
 }
 
 func (c *goHTTPClient) Get(url string, headers ...http.Header) (*Response, error) {
-	response, err := c.do(http.MethodGet, url, getHeader(headers...), nil)
+	return c.GetWithContext(context.Background(), url, headers...)
+}
+
+func (c *goHTTPClient) Post(url string, body []byte, headers ...http.Header) (*Response, error) {
+	return c.PostWithContext(context.Background(), url, body, headers...)
+}
+
+func (c *goHTTPClient) Put(url string, body []byte, headers ...http.Header) (*Response, error) {
+	return c.PutWithContext(context.Background(), url, body, headers...)
+}
+
+func (c *goHTTPClient) Delete(url string, body []byte, headers ...http.Header) (*Response, error) {
+	return c.DeleteWithContext(context.Background(), url, body, headers...)
+}
+
+func (c *goHTTPClient) Patch(url string, body []byte, headers ...http.Header) (*Response, error) {
+	return c.PatchWithContext(context.Background(), url, body, headers...)
+}
+
+func (c *goHTTPClient) Head(url string, body []byte, headers ...http.Header) (*Response, error) {
+	return c.HeadWithContext(context.Background(), url, body, headers...)
+}
+
+// GetWithContext is like Get but carries ctx to the request.
+// The request is aborted as soon as ctx is canceled or its deadline passes, in which case
+// ctx.Err() (context.Canceled or context.DeadlineExceeded) is returned instead of the transport error.
+// A deadline on ctx is honoured alongside the configured request timeout, whichever expires first.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+//	defer cancel()
+//	response, err := client.GetWithContext(ctx, "https://www.google.com")
+//	if errors.Is(err, context.DeadlineExceeded) {
+//		log.Println("request took too long")
+//	}
+func (c *goHTTPClient) GetWithContext(ctx context.Context, url string, headers ...http.Header) (*Response, error) {
+	response, err := c.DoWithContext(ctx, newMethodRequest(http.MethodGet, url, nil, headers...))
 	// restore timeout state to default in case it was disabled
 	if c.builder.Timeout.GetRequestTimeout() == 0 {
 		c.builder.Timeout = c.builder.Timeout.Enable()
@@ -51,47 +133,175 @@ func (c *goHTTPClient) Get(url string, headers ...http.Header) (*Response, error
 	return response, nil
 }
 
-func (c *goHTTPClient) Post(url string, body []byte, headers ...http.Header) (*Response, error) {
-	response, err := c.do(http.MethodPost, url, getHeader(headers...), body)
+// PostWithContext is like Post but carries ctx to the request. See GetWithContext for the cancellation semantics.
+func (c *goHTTPClient) PostWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error) {
+	response, err := c.DoWithContext(ctx, newMethodRequest(http.MethodPost, url, body, headers...))
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
-func (c *goHTTPClient) Put(url string, body []byte, headers ...http.Header) (*Response, error) {
-	response, err := c.do(http.MethodPut, url, getHeader(headers...), body)
+// PutWithContext is like Put but carries ctx to the request. See GetWithContext for the cancellation semantics.
+func (c *goHTTPClient) PutWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error) {
+	response, err := c.DoWithContext(ctx, newMethodRequest(http.MethodPut, url, body, headers...))
 	if err != nil {
 		return nil, err
 	}
-
 	return response, nil
 }
 
-func (c *goHTTPClient) Delete(url string, body []byte, headers ...http.Header) (*Response, error) {
-	response, err := c.do(http.MethodDelete, url, getHeader(headers...), body)
+// DeleteWithContext is like Delete but carries ctx to the request. See GetWithContext for the cancellation semantics.
+func (c *goHTTPClient) DeleteWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error) {
+	response, err := c.DoWithContext(ctx, newMethodRequest(http.MethodDelete, url, body, headers...))
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
-func (c *goHTTPClient) Patch(url string, body []byte, headers ...http.Header) (*Response, error) {
-	response, err := c.do(http.MethodPatch, url, getHeader(headers...), body)
+// PatchWithContext is like Patch but carries ctx to the request. See GetWithContext for the cancellation semantics.
+func (c *goHTTPClient) PatchWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error) {
+	response, err := c.DoWithContext(ctx, newMethodRequest(http.MethodPatch, url, body, headers...))
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
-func (c *goHTTPClient) Head(url string, body []byte, headers ...http.Header) (*Response, error) {
-	response, err := c.do(http.MethodHead, url, getHeader(headers...), body)
+// HeadWithContext is like Head but carries ctx to the request. See GetWithContext for the cancellation semantics.
+func (c *goHTTPClient) HeadWithContext(ctx context.Context, url string, body []byte, headers ...http.Header) (*Response, error) {
+	response, err := c.DoWithContext(ctx, newMethodRequest(http.MethodHead, url, body, headers...))
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
+// Stream sends a request like the other methods but does not buffer the response body.
+// The returned Response exposes the body read from the connection through its Body method,
+// which is the way to download large files or consume streaming APIs. The caller must close the Response.
+// The request timeout of the client still applies while the body is read, so it must be
+// large enough or disabled for long downloads; use ctx to bound the request instead.
+//
+// Example:
+//
+//	response, err := client.Stream(ctx, http.MethodGet, "https://example.com/large.iso", nil)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer response.Close()
+//	_, err = io.Copy(file, response.Body())
+func (c *goHTTPClient) Stream(ctx context.Context, method Method, url string, body []byte, headers ...http.Header) (*Response, error) {
+	return c.DoWithContext(ctx, newMethodRequest(method, url, body, headers...).SetStream(true))
+}
+
+// Upload sends a request whose body is read from body while it is sent, so that large payloads
+// such as files never have to be loaded in memory.
+//
+//   - contentLength is the length of the body, or -1 if unknown in which case the body is sent with
+//     the chunked transfer encoding. The length of an io.Seeker is computed when it is unknown.
+//   - When body is an io.Seeker, e.g. an *os.File, the request can be replayed on retries and redirects
+//     by seeking back to its current offset. Other readers are sent only once.
+//   - body is never closed by the client.
+//
+// Example:
+//
+//	file, err := os.Open("backup.tar.gz")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer file.Close()
+//	response, err := client.Upload(ctx, http.MethodPut, "https://example.com/backups/latest", file, -1)
+func (c *goHTTPClient) Upload(ctx context.Context, method Method, url string, body io.Reader, contentLength int64, headers ...http.Header) (*Response, error) {
+	return c.DoWithContext(ctx, NewRequest(method, url).SetHeaders(getHeader(headers...)).SetBodyReader(body, contentLength))
+}
+
+// PostValue sends a POST request whose body is v encoded according to the Content-Type header of the request,
+// or of the client if the request has none. Json, xml, yaml, url encoded forms and text are supported,
+// which mirrors Response.Unmarshal. When no Content-Type is configured, v is sent as json.
+//
+// Example:
+//
+//	pet := Pet{ID: 1, Name: "doggie"}
+//	response, err := client.PostValue("https://petstore.swagger.io/v2/pet", pet)
+func (c *goHTTPClient) PostValue(url string, v interface{}, headers ...http.Header) (*Response, error) {
+	return c.Do(NewRequest(http.MethodPost, url).SetHeaders(getHeader(headers...)).SetValue(v))
+}
+
+// PutValue sends a PUT request whose body is v encoded according to the Content-Type header, see PostValue.
+func (c *goHTTPClient) PutValue(url string, v interface{}, headers ...http.Header) (*Response, error) {
+	return c.Do(NewRequest(http.MethodPut, url).SetHeaders(getHeader(headers...)).SetValue(v))
+}
+
+// PatchValue sends a PATCH request whose body is v encoded according to the Content-Type header, see PostValue.
+func (c *goHTTPClient) PatchValue(url string, v interface{}, headers ...http.Header) (*Response, error) {
+	return c.Do(NewRequest(http.MethodPatch, url).SetHeaders(getHeader(headers...)).SetValue(v))
+}
+
+// PostMultipart sends a POST request with a multipart/form-data body.
+// The Content-Type header is set with the boundary of the body, and the parts are streamed
+// with the chunked transfer encoding. The request is replayed on retries only if every part can be written twice.
+//
+// Example:
+//
+//	body := go_requests.NewMultipart().
+//		AddField("additionalMetadata", "profile").
+//		AddFile("file", "/tmp/doggie.png")
+//	response, err := client.PostMultipart(ctx, "https://petstore.swagger.io/v2/pet/1/uploadImage", body)
+func (c *goHTTPClient) PostMultipart(ctx context.Context, url string, body Multipart, headers ...http.Header) (*Response, error) {
+	return c.DoWithContext(ctx, NewRequest(http.MethodPost, url).SetHeaders(getHeader(headers...)).SetMultipart(body))
+}
+
+// PutMultipart sends a PUT request with a multipart/form-data body, see PostMultipart.
+func (c *goHTTPClient) PutMultipart(ctx context.Context, url string, body Multipart, headers ...http.Header) (*Response, error) {
+	return c.DoWithContext(ctx, NewRequest(http.MethodPut, url).SetHeaders(getHeader(headers...)).SetMultipart(body))
+}
+
+// Do sends the request. See DoWithContext.
+func (c *goHTTPClient) Do(req Request) (*Response, error) {
+	return c.DoWithContext(context.Background(), req)
+}
+
+// DoWithContext sends the request bound to ctx. See GetWithContext for the cancellation semantics.
+// The headers, query params, authorization, timeout and body of the request only apply to it,
+// so a client can send requests from several goroutines at once.
+//
+// Example:
+//
+//	req := go_requests.NewRequest(http.MethodPost, "https://petstore.swagger.io/v2/store/order").
+//		SetHeader("Content-Type", "application/json").
+//		SetValue(order).
+//		SetTimeout(5 * time.Second)
+//	response, err := client.DoWithContext(ctx, req)
+func (c *goHTTPClient) DoWithContext(ctx context.Context, req Request) (*Response, error) {
+	r, ok := req.(*requestImpl)
+	if !ok {
+		return nil, errors.New("unsupported Request implementation")
+	}
+	if r.authorization != nil {
+		ctx = WithAuthorization(ctx, r.authorization)
+	}
+	if r.stream {
+		ctx = withStream(ctx)
+	}
+	if r.hedgePolicy != nil {
+		ctx = withHedgePolicy(ctx, r.hedgePolicy)
+	}
+	if r.timeout <= 0 {
+		return c.do(ctx, r)
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	response, err := c.do(ctx, r)
+	if err == nil && response.stream != nil {
+		// the timeout keeps running while the body is read
+		response.stream = &cancelReadCloser{ReadCloser: response.stream, cancel: cancel}
+		return response, nil
+	}
+	cancel()
+	return response, err
+}
+
 // DisableTimeouts disables the timeouts for the client requests
 // Example:
 //
@@ -112,10 +322,22 @@ func (c *goHTTPClient) EnableTimeouts() {
 func (c *goHTTPClient) getClient() *http.Client {
 	c.clientOnce.Do(func() {
 		if c.builder.cstClient != nil {
-			c.client = c.builder.cstClient
+			// the custom client may be shared, so it is copied rather than modified
+			client := *c.builder.cstClient
+			if c.builder.cookieJar != nil {
+				client.Jar = c.builder.cookieJar
+			}
+			if c.builder.redirectPolicy != nil {
+				client.CheckRedirect = c.builder.redirectPolicy.CheckRedirect
+			}
+			if c.builder.hedgePolicy != nil {
+				client.Transport = c.hedgingTransport(client.Transport)
+			}
+			c.client = &client
 			return
 		}
 		c.client = &http.Client{
+			Jar:     c.builder.cookieJar,
 			Timeout: c.builder.Timeout.GetRequestTimeout(),
 			Transport: &http.Transport{
 				MaxIdleConnsPerHost:   c.builder.Timeout.GetMaxIdleConnections(),
@@ -125,11 +347,60 @@ func (c *goHTTPClient) getClient() *http.Client {
 				}).DialContext,
 			},
 		}
+		if c.builder.redirectPolicy != nil {
+			c.client.CheckRedirect = c.builder.redirectPolicy.CheckRedirect
+		}
+		if c.builder.hedgePolicy != nil {
+			c.client.Transport = c.hedgingTransport(c.client.Transport)
+		}
 	})
 	return c.client
 
 }
 
+// getHedgingClient returns the *http.client that sends the request of ctx. The transport of the client is only
+// wrapped by a hedgingTransport when a HedgePolicy can apply, so that it keeps its type otherwise.
+func (c *goHTTPClient) getHedgingClient(ctx context.Context) *http.Client {
+	client := c.getClient()
+	if _, ok := client.Transport.(*hedgingTransport); ok || ctx.Value(hedgeContextKey{}) == nil {
+		return client
+	}
+	// the request has its own policy, the copy shares the connections of the client
+	hedging := *client
+	hedging.Transport = c.hedgingTransport(client.Transport)
+	return &hedging
+}
+
+// hedgingTransport returns base, or http.DefaultTransport if nil, wrapped by a hedgingTransport with the
+// HedgePolicy and the RateLimiter of the builder.
+func (c *goHTTPClient) hedgingTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &hedgingTransport{base: base, policy: c.builder.hedgePolicy, limiter: c.builder.rateLimiter}
+}
+
+// Cookies returns the cookies of the cookie jar of the client that are sent with a request for rawURL.
+// A relative URL is resolved against the base URL of the Builder. Without a cookie jar, there are no cookies.
+//
+//	Example:
+//		cookies, err := client.Cookies("https://petstore.swagger.io/v2/user/login")
+func (c *goHTTPClient) Cookies(rawURL string) ([]*http.Cookie, error) {
+	jar := c.getClient().Jar
+	if jar == nil {
+		return nil, nil
+	}
+	resolved, err := resolveURL(c.builder.baseURL, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(resolved)
+	if err != nil {
+		return nil, err
+	}
+	return jar.Cookies(u), nil
+}
+
 // Headers sets the headers for the client
 func (c *goHTTPClient) Headers() Headers {
 	return c.builder.Headers()
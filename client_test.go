@@ -1,9 +1,15 @@
 package go_requests
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -305,3 +311,298 @@ func Test_goHTTPClient_Head(t *testing.T) {
 		)
 	}
 }
+
+func Test_goHTTPClient_GetWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		path    string
+		want    int
+		wantErr error
+	}{
+		{
+			name: "background",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			path: "/",
+			want: http.StatusOK,
+		},
+		{
+			name: "canceled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return canceled, func() {}
+			},
+			path:    "/",
+			wantErr: context.Canceled,
+		},
+		{
+			name: "deadline exceeded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			path:    "/slow",
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			client := NewBuilder().Build()
+			res, err := client.GetWithContext(ctx, server.URL+tt.path)
+			if tt.wantErr != nil {
+				if err != tt.wantErr {
+					t.Errorf("GetWithContext() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetWithContext() unexpected error = %v", err)
+			}
+			if res.StatusCode() != tt.want {
+				t.Errorf("GetWithContext() status = %v, want %v", res.StatusCode(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_goHTTPClient_PostWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(r.Method))
+	}))
+	defer server.Close()
+
+	client := NewBuilder().Build()
+	res, err := client.PostWithContext(context.Background(), server.URL, []byte("body"))
+	if err != nil {
+		t.Fatalf("PostWithContext() error = %v", err)
+	}
+	if res.String() != http.MethodPost {
+		t.Errorf("PostWithContext() body = %v, want %v", res.String(), http.MethodPost)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.PostWithContext(ctx, server.URL, []byte("body"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("PostWithContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func Test_goHTTPClient_Stream(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":`))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte(`"doggie"}`))
+	}))
+	defer server.Close()
+
+	client := NewBuilder().Build()
+	res, err := client.Stream(context.Background(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	defer func() {
+		_ = res.Close()
+	}()
+	if !res.IsStream() {
+		t.Fatalf("Stream() returned a buffered response")
+	}
+	// the response is returned before the whole body is sent
+	chunk := make([]byte, 8)
+	if _, err = io.ReadFull(res.Body(), chunk); err != nil || string(chunk) != `{"name":` {
+		t.Fatalf("Body() read = %q, %v", chunk, err)
+	}
+	close(release)
+	rest, err := io.ReadAll(res.Body())
+	if err != nil || string(rest) != `"doggie"}` {
+		t.Errorf("Body() read = %q, %v", rest, err)
+	}
+}
+
+func Test_goHTTPClient_StreamUnmarshal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	client := NewBuilder().Build()
+	res, err := client.Stream(context.Background(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	var got struct {
+		Name string `json:"name"`
+	}
+	if err = res.Unmarshal(&got); err != nil || got.Name != "doggie" {
+		t.Errorf("Unmarshal() = %v, %v", got, err)
+	}
+	if res.IsStream() {
+		t.Errorf("Unmarshal() did not buffer the body")
+	}
+	if res.String() != `{"name":"doggie"}` {
+		t.Errorf("String() = %v", res.String())
+	}
+	if err = res.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func Test_goHTTPClient_Upload(t *testing.T) {
+	type received struct {
+		body          string
+		contentLength int64
+		chunked       bool
+	}
+	var requests []received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, received{
+			body:          string(body),
+			contentLength: r.ContentLength,
+			chunked:       len(r.TransferEncoding) > 0 && r.TransferEncoding[0] == "chunked",
+		})
+		// the first attempt of every upload fails
+		if len(requests)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	file, err := os.CreateTemp(t.TempDir(), "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	_, _ = file.WriteString("header|payload")
+	_, _ = file.Seek(int64(len("header|")), io.SeekStart)
+
+	tests := []struct {
+		name          string
+		body          io.Reader
+		contentLength int64
+		want          []received
+	}{
+		{
+			name:          "seekable with unknown length is replayed",
+			body:          file,
+			contentLength: -1,
+			want: []received{
+				{body: "payload", contentLength: 7},
+				{body: "payload", contentLength: 7},
+			},
+		},
+		{
+			name:          "reader with known length is sent once",
+			body:          io.MultiReader(strings.NewReader("pay"), strings.NewReader("load")),
+			contentLength: 7,
+			want: []received{
+				{body: "payload", contentLength: 7},
+			},
+		},
+		{
+			name:          "reader with unknown length is chunked",
+			body:          io.MultiReader(strings.NewReader("payload")),
+			contentLength: -1,
+			want: []received{
+				{body: "payload", contentLength: -1, chunked: true},
+			},
+		},
+	}
+	builder := NewBuilder()
+	builder.SetRetryPolicy(NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond))
+	client := builder.Build()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			res, err := client.Upload(context.Background(), http.MethodPut, server.URL, tt.body, tt.contentLength)
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+			if res.Attempts() != len(tt.want) {
+				t.Errorf("Upload() attempts = %v, want %v", res.Attempts(), len(tt.want))
+			}
+			if !reflect.DeepEqual(requests, tt.want) {
+				t.Errorf("Upload() requests = %+v, want %+v", requests, tt.want)
+			}
+		})
+	}
+}
+
+func Test_goHTTPClient_PostValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(r.Header.Get("Content-Type") + " " + string(body)))
+	}))
+	defer server.Close()
+
+	pet := map[string]interface{}{"id": 1}
+	tests := []struct {
+		name        string
+		contentType string
+		headers     []http.Header
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "json by default",
+			want: `application/json {"id":1}`,
+		},
+		{
+			name:        "client content type",
+			contentType: "application/yaml",
+			want:        "application/yaml id: 1\n",
+		},
+		{
+			name:        "request content type wins",
+			contentType: "application/yaml",
+			headers:     []http.Header{{"Content-Type": {"application/json; charset=utf-8"}}},
+			want:        `application/json; charset=utf-8 {"id":1}`,
+		},
+		{
+			name:    "unsupported content type",
+			headers: []http.Header{{"Content-Type": {"application/octet-stream"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder()
+			if tt.contentType != "" {
+				builder.Headers().SetContentType(tt.contentType)
+			}
+			res, err := builder.Build().PostValue(server.URL, pet, tt.headers...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PostValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && res.String() != tt.want {
+				t.Errorf("PostValue() = %q, want %q", res.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package go_requests
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	"gopkg.in/yaml.v2"
+)
+
+// marshalBody encodes v into a request body of the given content type.
+//   - A []byte is sent as is whatever the content type.
+//   - json, xml and yaml values are encoded with the same packages that Response.Unmarshal uses.
+//   - Text values must be a string, or implement encoding.TextMarshaler or fmt.Stringer.
+//   - Url encoded forms accept url.Values, map[string]string and map[string][]string.
+//   - It returns UnsupportedContentType if the content type or the type of v is not supported.
+func marshalBody(contentType ContentType, v interface{}) ([]byte, error) {
+	if body, ok := v.([]byte); ok {
+		return body, nil
+	}
+	switch contentType {
+	case jsonContentType:
+		return json.Marshal(v)
+	case xmlContentType:
+		return xml.Marshal(v)
+	case yamlContentType:
+		return yaml.Marshal(v)
+	case textContentType:
+		return marshalText(v)
+	case formContentType:
+		return marshalForm(v)
+	default:
+		return nil, UnsupportedContentType()
+	}
+}
+
+// marshalText encodes a string or an encoding.TextMarshaler.
+func marshalText(v interface{}) ([]byte, error) {
+	switch value := v.(type) {
+	case string:
+		return []byte(value), nil
+	case encoding.TextMarshaler:
+		return value.MarshalText()
+	case fmt.Stringer:
+		return []byte(value.String()), nil
+	}
+	return nil, UnsupportedContentType()
+}
+
+// marshalForm encodes the values of an url encoded form.
+func marshalForm(v interface{}) ([]byte, error) {
+	switch value := v.(type) {
+	case url.Values:
+		return []byte(value.Encode()), nil
+	case map[string][]string:
+		return []byte(url.Values(value).Encode()), nil
+	case map[string]string:
+		values := make(url.Values, len(value))
+		for key, item := range value {
+			values.Set(key, item)
+		}
+		return []byte(values.Encode()), nil
+	}
+	return nil, UnsupportedContentType()
+}
@@ -0,0 +1,44 @@
+package go_requests
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_marshalBody(t *testing.T) {
+	type pet struct {
+		ID   int    `json:"id" xml:"id" yaml:"id"`
+		Name string `json:"name" xml:"name" yaml:"name"`
+	}
+	tests := []struct {
+		name        string
+		contentType ContentType
+		v           interface{}
+		want        string
+		wantErr     bool
+	}{
+		{name: "json", contentType: jsonContentType, v: pet{ID: 1, Name: "doggie"}, want: `{"id":1,"name":"doggie"}`},
+		{name: "xml", contentType: xmlContentType, v: pet{ID: 1, Name: "doggie"}, want: `<pet><id>1</id><name>doggie</name></pet>`},
+		{name: "yaml", contentType: yamlContentType, v: pet{ID: 1, Name: "doggie"}, want: "id: 1\nname: doggie\n"},
+		{name: "text string", contentType: textContentType, v: "doggie", want: "doggie"},
+		{name: "text marshaler", contentType: textContentType, v: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), want: "2023-01-02T03:04:05Z"},
+		{name: "text unsupported", contentType: textContentType, v: 42, wantErr: true},
+		{name: "form values", contentType: formContentType, v: url.Values{"b": {"2", "3"}, "a": {"x y"}}, want: "a=x+y&b=2&b=3"},
+		{name: "form map", contentType: formContentType, v: map[string]string{"name": "doggie"}, want: "name=doggie"},
+		{name: "form unsupported", contentType: formContentType, v: pet{}, wantErr: true},
+		{name: "bytes", contentType: noneContentType, v: []byte("raw"), want: "raw"},
+		{name: "unsupported content type", contentType: noneContentType, v: pet{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalBody(tt.contentType, tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("marshalBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("marshalBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package go_requests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CookieJar is an in-memory http.CookieJar that can be saved to and loaded from a file, so that a session
+// survives between the runs of a program. It is set on the Builder with SetCookieJar.
+//
+// Cookies are stored by net/http/cookiejar, which decides which cookies are accepted and sent. Session cookies,
+// which have no expiry, are saved too.
+type CookieJar interface {
+	http.CookieJar
+	// Save writes the cookies of the jar that have not expired to w, as JSON.
+	Save(w io.Writer) error
+	// Load adds the cookies written by Save to the jar. Expired cookies are skipped.
+	Load(r io.Reader) error
+	// SaveFile saves the cookies of the jar to the file at path, which is readable by its owner only.
+	SaveFile(path string) error
+	// LoadFile loads the cookies saved to the file at path. A file that does not exist leaves the jar empty.
+	LoadFile(path string) error
+	// Clear removes every cookie of the jar.
+	Clear()
+}
+
+// savedCookie is a cookie of a jar as saved by CookieJar.Save, with the URL that set it
+type savedCookie struct {
+	URL      string        `json:"url"`
+	Name     string        `json:"name"`
+	Value    string        `json:"value"`
+	Path     string        `json:"path,omitempty"`
+	Domain   string        `json:"domain,omitempty"`
+	Expires  *time.Time    `json:"expires,omitempty"`
+	Secure   bool          `json:"secure,omitempty"`
+	HttpOnly bool          `json:"http_only,omitempty"`
+	SameSite http.SameSite `json:"same_site,omitempty"`
+}
+
+// savedCookieKey identifies a cookie of a jar: cookies with the same key replace each other
+type savedCookieKey struct {
+	host, domain, path, name string
+}
+
+// cookieJarImpl is the implementation of the CookieJar interface.
+// net/http/cookiejar does not expose the attributes of its cookies, so the cookies it is given are kept as well
+// to be saved.
+type cookieJarImpl struct {
+	mu      sync.Mutex
+	options *cookiejar.Options
+	jar     *cookiejar.Jar
+	cookies map[savedCookieKey]savedCookie
+	// now returns the current time, it is replaced in tests
+	now func() time.Time
+}
+
+// NewCookieJar returns an empty CookieJar.
+//
+// publicSuffixList prevents the servers from setting cookies for a whole public suffix, e.g. "co.uk".
+// It is typically publicsuffix.List of golang.org/x/net/publicsuffix. With a nil list, the domain of a cookie is only
+// checked against the host that sets it, as net/http/cookiejar does.
+//
+//	Example:
+//		jar := go_requests.NewCookieJar(publicsuffix.List)
+//		if err := jar.LoadFile(sessionFile); err != nil {
+//			return err
+//		}
+//		builder.SetCookieJar(jar)
+//		...
+//		err := jar.SaveFile(sessionFile)
+func NewCookieJar(publicSuffixList cookiejar.PublicSuffixList) CookieJar {
+	options := &cookiejar.Options{PublicSuffixList: publicSuffixList}
+	// cookiejar.New never returns an error
+	jar, _ := cookiejar.New(options)
+	return &cookieJarImpl{
+		options: options,
+		jar:     jar,
+		cookies: make(map[savedCookieKey]savedCookie),
+		now:     time.Now,
+	}
+}
+
+// SetCookies stores the cookies received from u.
+func (j *cookieJarImpl) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar.SetCookies(u, cookies)
+	now := j.now()
+	for _, cookie := range cookies {
+		key := savedCookieKey{domain: cookie.Domain, path: cookie.Path, name: cookie.Name}
+		if key.domain == "" {
+			// a cookie without domain is only sent to the host that set it
+			key.host = u.Hostname()
+		}
+		if key.path == "" {
+			key.path = defaultCookiePath(u.Path)
+		}
+		saved := savedCookie{
+			URL:      (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String(),
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+			SameSite: cookie.SameSite,
+		}
+		switch {
+		case cookie.MaxAge < 0:
+			delete(j.cookies, key)
+			continue
+		case cookie.MaxAge > 0:
+			expires := now.Add(time.Duration(cookie.MaxAge) * time.Second)
+			saved.Expires = &expires
+		case !cookie.Expires.IsZero():
+			if !cookie.Expires.After(now) {
+				delete(j.cookies, key)
+				continue
+			}
+			expires := cookie.Expires
+			saved.Expires = &expires
+		}
+		if !j.accepted(u, key.path, cookie) {
+			// the jar rejects the cookies of another domain or of a public suffix, they are not saved either
+			continue
+		}
+		j.cookies[key] = saved
+	}
+}
+
+// accepted returns true if the wrapped jar stored the cookie received from u with the given path.
+// It must be called with j.mu held.
+func (j *cookieJarImpl) accepted(u *url.URL, path string, cookie *http.Cookie) bool {
+	// the secure cookies are only sent over https
+	for _, sent := range j.jar.Cookies(&url.URL{Scheme: "https", Host: u.Host, Path: path}) {
+		if sent.Name == cookie.Name && sent.Value == cookie.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultCookiePath returns the path of a cookie set without path by a request for urlPath (RFC 6265 section 5.1.4).
+func defaultCookiePath(urlPath string) string {
+	i := strings.LastIndexByte(urlPath, '/')
+	if i <= 0 {
+		return "/"
+	}
+	return urlPath[:i]
+}
+
+// Cookies returns the cookies to send in a request for u.
+func (j *cookieJarImpl) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.jar.Cookies(u)
+}
+
+// Save writes the cookies of the jar that have not expired to w, sorted so that the output is stable.
+func (j *cookieJarImpl) Save(w io.Writer) error {
+	j.mu.Lock()
+	now := j.now()
+	cookies := make([]savedCookie, 0, len(j.cookies))
+	for _, cookie := range j.cookies {
+		if cookie.Expires == nil || cookie.Expires.After(now) {
+			cookies = append(cookies, cookie)
+		}
+	}
+	j.mu.Unlock()
+	sort.Slice(cookies, func(a, b int) bool {
+		if cookies[a].URL != cookies[b].URL {
+			return cookies[a].URL < cookies[b].URL
+		}
+		if cookies[a].Path != cookies[b].Path {
+			return cookies[a].Path < cookies[b].Path
+		}
+		return cookies[a].Name < cookies[b].Name
+	})
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(cookies)
+}
+
+// Load adds the cookies written by Save to the jar.
+func (j *cookieJarImpl) Load(r io.Reader) error {
+	var cookies []savedCookie
+	if err := json.NewDecoder(r).Decode(&cookies); err != nil {
+		return fmt.Errorf("cookie jar: %w", err)
+	}
+	now := j.now()
+	for _, saved := range cookies {
+		u, err := url.Parse(saved.URL)
+		if err != nil {
+			return fmt.Errorf("cookie jar: %w", err)
+		}
+		cookie := &http.Cookie{
+			Name:     saved.Name,
+			Value:    saved.Value,
+			Path:     saved.Path,
+			Domain:   saved.Domain,
+			Secure:   saved.Secure,
+			HttpOnly: saved.HttpOnly,
+			SameSite: saved.SameSite,
+		}
+		if saved.Expires != nil {
+			if !saved.Expires.After(now) {
+				continue
+			}
+			cookie.Expires = *saved.Expires
+		}
+		j.SetCookies(u, []*http.Cookie{cookie})
+	}
+	return nil
+}
+
+// SaveFile saves the cookies of the jar to the file at path. The file is replaced atomically, so that a
+// failure does not lose the session saved previously.
+func (j *cookieJarImpl) SaveFile(path string) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cookie jar: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	if err = j.Save(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("cookie jar: %w", err)
+	}
+	if err = os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("cookie jar: %w", err)
+	}
+	return nil
+}
+
+// LoadFile loads the cookies saved to the file at path.
+func (j *cookieJarImpl) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cookie jar: %w", err)
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+	return j.Load(file)
+}
+
+// Clear removes every cookie of the jar.
+func (j *cookieJarImpl) Clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar, _ = cookiejar.New(j.options)
+	j.cookies = make(map[savedCookieKey]savedCookie)
+}
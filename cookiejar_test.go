@@ -0,0 +1,153 @@
+package go_requests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSuffixList is a public suffix list where "com" and "co.uk" are the only public suffixes
+type testSuffixList struct{}
+
+func (testSuffixList) PublicSuffix(domain string) string {
+	if strings.HasSuffix(domain, ".co.uk") || domain == "co.uk" {
+		return "co.uk"
+	}
+	return domain[strings.LastIndexByte(domain, '.')+1:]
+}
+
+func (testSuffixList) String() string {
+	return "test"
+}
+
+func Test_goHTTPClient_cookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t", Path: "/"})
+		case "/me":
+			cookie, err := r.Cookie("session")
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(cookie.Value))
+		}
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	jar := NewCookieJar(nil)
+	builder := NewBuilder()
+	builder.SetBaseURL(server.URL)
+	builder.SetCookieJar(jar)
+	client := builder.Build()
+	if _, err := client.Get("/login"); err != nil {
+		t.Fatal(err)
+	}
+	cookies, err := client.Cookies("/me")
+	if err != nil || len(cookies) != 1 || cookies[0].Value != "s3cr3t" {
+		t.Fatalf("Cookies() = %v, %v, want the session cookie", cookies, err)
+	}
+	if err = jar.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new run loads the session saved by the previous one
+	loaded := NewCookieJar(nil)
+	if err = loaded.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	builder = NewBuilder()
+	builder.SetCookieJar(loaded)
+	response, err := builder.Build().Get(server.URL + "/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode() != http.StatusOK || response.String() != "s3cr3t" {
+		t.Errorf("response = %d %q, want 200 s3cr3t", response.StatusCode(), response.String())
+	}
+}
+
+func Test_goHTTPClient_Cookies_noJar(t *testing.T) {
+	cookies, err := NewBuilder().Build().Cookies("https://example.com")
+	if err != nil || cookies != nil {
+		t.Errorf("Cookies() = %v, %v, want none", cookies, err)
+	}
+}
+
+func Test_goHTTPClient_cookieJar_customClient(t *testing.T) {
+	custom := &http.Client{Timeout: time.Second}
+	builder := NewBuilder()
+	builder.SetHTTPClient(custom)
+	builder.SetCookieJar(NewCookieJar(nil))
+	client := builder.Build().(*goHTTPClient)
+	if custom.Jar != nil {
+		t.Error("the custom http client was modified")
+	}
+	if got := client.getClient(); got.Jar == nil || got.Timeout != time.Second {
+		t.Errorf("getClient() = %+v, want a copy of the custom client with the jar", got)
+	}
+}
+
+func Test_cookieJarImpl_SaveLoad(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	jar := NewCookieJar(testSuffixList{}).(*cookieJarImpl)
+	jar.now = func() time.Time { return now }
+	u, _ := url.Parse("https://www.example.co.uk/account/login")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "1"},
+		{Name: "lang", Value: "en", Path: "/", Domain: "example.co.uk", MaxAge: 3600},
+		{Name: "suffix", Value: "x", Domain: "co.uk"},
+		{Name: "foreign", Value: "x", Domain: "other.com"},
+		{Name: "expired", Value: "x", Expires: now.Add(-time.Hour)},
+	})
+	jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "2"}})
+	var saved bytes.Buffer
+	if err := jar.Save(&saved); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"name": "session"`, `"value": "2"`, `"name": "lang"`, `"expires": "` + now.Add(time.Hour).Format(time.RFC3339) + `"`} {
+		if !strings.Contains(saved.String(), want) {
+			t.Errorf("Save() = %s, want it to contain %s", saved.String(), want)
+		}
+	}
+	for _, rejected := range []string{`"suffix"`, `"foreign"`} {
+		if strings.Contains(saved.String(), rejected) {
+			t.Errorf("Save() = %s, want no %s cookie, which the jar rejected", saved.String(), rejected)
+		}
+	}
+	if strings.Contains(saved.String(), `"expired"`) || strings.Count(saved.String(), `"name": "session"`) != 1 {
+		t.Errorf("Save() = %s, want no expired cookie and a single session cookie", saved.String())
+	}
+
+	loaded := NewCookieJar(testSuffixList{})
+	if err := loaded.Load(&saved); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := url.Parse("https://shop.example.co.uk/account/cart")
+	if got := loaded.Cookies(other); len(got) != 1 || got[0].Name != "lang" {
+		t.Errorf("Cookies(other) = %v, want lang only", got)
+	}
+	if got := loaded.Cookies(u); len(got) != 2 {
+		t.Errorf("Cookies(u) = %v, want session and lang", got)
+	}
+	if got := loaded.Cookies(&url.URL{Scheme: "https", Host: "evil.co.uk", Path: "/"}); len(got) != 0 {
+		t.Errorf("Cookies(evil) = %v, want no cookie set for the public suffix", got)
+	}
+	loaded.Clear()
+	if got := loaded.Cookies(u); len(got) != 0 {
+		t.Errorf("Cookies() after Clear = %v, want none", got)
+	}
+}
+
+func Test_cookieJarImpl_LoadFile_missing(t *testing.T) {
+	if err := NewCookieJar(nil).LoadFile(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("LoadFile() error = %v, want nil", err)
+	}
+}
@@ -2,9 +2,11 @@ package go_requests
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 )
 
 // do is the main method to make the request
@@ -33,42 +35,227 @@ import (
 //		// Return the response
 //		return c.client.Do(req)
 //	}
-func (c *goHTTPClient) do(method Method, url string, headers http.Header, body []byte) (*Response, error) {
-	var req *http.Request
-	var err error
-	availableHeaders := c.getHeaders(headers)
-	if body != nil {
-		reader := bytes.NewReader(body)
-		req, err = http.NewRequest(string(method), url, reader)
-	} else {
-		req, err = http.NewRequest(string(method), url, nil)
+//
+// The request is bound to ctx. If ctx is done before the response body has been read,
+// ctx.Err() is returned so that callers can tell cancellation and deadlines apart from transport errors.
+//
+// The URL of r is resolved against the base URL of the builder. The headers of r take precedence over the ones
+// of the client, and the query params set on the client are added to the ones of r and reset.
+func (c *goHTTPClient) do(ctx context.Context, r *requestImpl) (*Response, error) {
+	rawURL, err := r.resolvedURL(c.builder.baseURL)
+	if err != nil {
+		return nil, err
 	}
-	if c.QueryParams().Len() > 0 {
-		q := req.URL.Query()
-		for key, value := range c.QueryParams().Values() {
-			q.Add(key, value)
+	headers := r.headers
+	body, contentLength := r.body, r.contentLength
+	switch {
+	case r.rawBody != nil:
+		body = bytes.NewReader(r.rawBody)
+	case r.multipart != nil:
+		headers = headers.Clone()
+		headers.Set(string(HeaderTypeContentType), r.multipart.ContentType())
+		multipartBody, err := newMultipartBody(r.multipart)
+		if err != nil {
+			return nil, err
+		}
+		body, contentLength = multipartBody, -1
+	case r.hasValue:
+		contentType := c.getHeaders(headers).Get(string(HeaderTypeContentType))
+		if contentType == "" {
+			// values are sent as json unless a Content-Type is configured
+			contentType = string(jsonContentType)
+			headers = headers.Clone()
+			headers.Set(string(HeaderTypeContentType), contentType)
 		}
-		req.URL.RawQuery = q.Encode()
-		c.QueryParams().Reset()
+		encoded, err := marshalBody(parseContentType(contentType), r.value)
+		if err != nil {
+			return nil, err
+		}
+		body, contentLength = bodyReader(encoded), int64(len(encoded))
 	}
-
+	availableHeaders := c.getHeaders(headers)
+	authorization := c.getAuthorization(ctx, headers)
+	if authorization != nil && authorization.IsDigest() {
+		// the digest middleware answers the challenge of the server with the authorization of the context
+		ctx = WithAuthorization(ctx, authorization)
+	}
+	if availableHeaders.Get("Authorization") != "" || authorization != nil && !authorization.IsDigest() {
+		// the middlewares that authorize the requests leave the Authorization header of the caller alone
+		ctx = withExplicitAuthorization(ctx)
+	}
+	req, err := newRequest(ctx, r.method, rawURL, body, contentLength)
 	if err != nil {
 		return nil, errors.New("unable to create request")
 	}
+	// the query params are appended to the query of the URL, each with its own array style
+	for _, params := range []QueryParams{c.takeQueryParams(), r.queryParams} {
+		if params == nil || params.Len() == 0 {
+			continue
+		}
+		if req.URL.RawQuery != "" {
+			req.URL.RawQuery += "&"
+		}
+		req.URL.RawQuery += params.Encode()
+	}
 	// Set all set Headers to the http request
 	req.Header = availableHeaders
-	// Return the response
-	c.client = c.getClient()
-	response, err := c.client.Do(req)
+	if authorization != nil && !authorization.IsDigest() {
+		req.Header.Set("Authorization", authorization.String())
+	}
+	return c.doWithRetry(ctx, req)
+}
+
+// newRequest returns a new request with the given body.
+// Bodies that are not in-memory readers are never closed, they belong to the caller. If such a body is an io.Seeker,
+// its length is computed when unknown and the request can be replayed by seeking back to the current offset.
+func newRequest(ctx context.Context, method Method, url string, body io.Reader, contentLength int64) (*http.Request, error) {
+	switch body := body.(type) {
+	case nil, *bytes.Buffer, *bytes.Reader, *strings.Reader:
+		return http.NewRequestWithContext(ctx, string(method), url, body)
+	case *multipartBody:
+		req, err := http.NewRequestWithContext(ctx, string(method), url, body)
+		if err != nil {
+			_ = body.Close()
+			return nil, err
+		}
+		req.ContentLength = -1
+		req.GetBody = body.getBody()
+		return req, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, string(method), url, io.NopCloser(body))
 	if err != nil {
 		return nil, err
 	}
+	if seeker, ok := body.(io.Seeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			if contentLength < 0 {
+				if end, err := seeker.Seek(0, io.SeekEnd); err == nil {
+					contentLength = end - offset
+				}
+				if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+					return nil, err
+				}
+			}
+			req.GetBody = func() (io.ReadCloser, error) {
+				if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+					return nil, err
+				}
+				return io.NopCloser(body), nil
+			}
+		}
+	}
+	req.ContentLength = contentLength
+	if contentLength == 0 {
+		req.Body = http.NoBody
+		req.GetBody = func() (io.ReadCloser, error) { return http.NoBody, nil }
+	}
+	return req, nil
+}
+
+// bodyReader returns a reader of the body, or nil if there is no body.
+func bodyReader(body []byte) io.Reader {
+	if body == nil {
+		return nil
+	}
+	return bytes.NewReader(body)
+}
+
+// doWithRetry sends the request, attempting it again as long as the retry policy of the builder allows it.
+// Every attempt is a clone of req, so that the headers set by the middlewares on an attempt do not leak into the
+// next one. The body of the request is rewound from req.GetBody before every new attempt.
+func (c *goHTTPClient) doWithRetry(ctx context.Context, req *http.Request) (*Response, error) {
+	policy := c.builder.retryPolicy
+	roundTrip := chain(c.send, c.middlewares()...)
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.Clone(ctx)
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+		response, err := roundTrip(attemptReq)
+		// the transport closes the body, unless a middleware answered without sending the request
+		if attemptReq.Body != nil {
+			_ = attemptReq.Body.Close()
+		}
+		if response != nil {
+			response.attempts = attempt
+		}
+		if policy == nil || attempt >= policy.GetMaxAttempts() || !policy.ShouldRetry(req.Method, response, err) {
+			return response, err
+		}
+		// a request with a body that cannot be replayed can only be attempted once
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return response, err
+		}
+		if response != nil {
+			_ = response.Close()
+		}
+		if sleepErr := sleep(ctx, policy.Backoff(attempt, response)); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+}
+
+// middlewares returns the middlewares registered on the builder followed by the internal ones
+// that implement the features configured on the builder.
+func (c *goHTTPClient) middlewares() []Middleware {
+	middlewares := make([]Middleware, 0, len(c.builder.middlewares)+6)
+	middlewares = append(middlewares, c.builder.middlewares...)
+	if c.builder.cacheStorage != nil {
+		middlewares = append(middlewares, cacheMiddleware(c.builder.cacheStorage))
+	}
+	if c.builder.circuitBreaker != nil {
+		middlewares = append(middlewares, circuitBreakerMiddleware(c.builder.circuitBreaker))
+	}
+	if c.builder.rateLimiter != nil {
+		middlewares = append(middlewares, rateLimitMiddleware(c.builder.rateLimiter))
+	}
+	if c.builder.tokenSource != nil {
+		middlewares = append(middlewares, oauth2Middleware(c.builder.tokenSource))
+	}
+	middlewares = append(middlewares, c.digest.middleware())
+	if c.builder.sigV4Signer != nil {
+		middlewares = append(middlewares, sigV4Middleware(c.builder.sigV4Signer))
+	}
+	return middlewares
+}
+
+// send executes a single attempt of the request and reads the whole response body,
+// unless the request is streamed in which case the body is left to the caller.
+// It is the innermost RoundTripFunc of the middleware chain.
+func (c *goHTTPClient) send(req *http.Request) (*Response, error) {
+	ctx := req.Context()
+	response, err := c.getHedgingClient(ctx).Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	if isStream(ctx) {
+		return &Response{
+			statusCode:  response.StatusCode,
+			header:      response.Header,
+			stream:      response.Body,
+			status:      response.Status,
+			contentType: response.Header.Get("Content-Type"),
+			attempts:    1,
+			url:         response.Request.URL,
+			redirects:   redirectChain(response),
+		}, nil
+	}
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(response.Body)
 
 	responseBody, err := io.ReadAll(response.Body)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, errors.New("unable to read response body. Error: " + err.Error())
 	}
 	finalResponse := Response{
@@ -77,24 +264,66 @@ func (c *goHTTPClient) do(method Method, url string, headers http.Header, body [
 		body:        responseBody,
 		status:      response.Status,
 		contentType: response.Header.Get("Content-Type"),
+		attempts:    1,
+		url:         response.Request.URL,
+		redirects:   redirectChain(response),
 	}
 	return &finalResponse, nil
 }
 
 // getHeaders returns the Headers that are set by the user and the default Headers that are set by the client
+// Every value of a header is kept and the keys are canonicalized. A header set by the user replaces all the values
+// of the default header with the same key.
 func (c *goHTTPClient) getHeaders(headers http.Header) http.Header {
 	res := make(http.Header)
 	// Set common Headers to the request
-	for header, value := range c.builder.Headers().GetAll() {
-		if len(value) > 0 {
-			res.Set(header, value[0])
+	for header, values := range c.builder.Headers().GetAll() {
+		if len(values) > 0 {
+			res[http.CanonicalHeaderKey(header)] = append([]string(nil), values...)
 		}
 	}
-	// Set Headers to the request
-	for header, value := range headers {
-		if len(value) > 0 {
-			res.Set(header, value[0])
+	// Set Headers to the request. They replace all the values of the common header with the same key.
+	requestHeaders := make(http.Header)
+	for header, values := range headers {
+		for _, value := range values {
+			requestHeaders.Add(header, value)
 		}
 	}
+	for header, values := range requestHeaders {
+		res[header] = values
+	}
 	return res
 }
+
+// streamContextKey is the context key that marks a request whose response body must not be buffered
+type streamContextKey struct{}
+
+// withStream returns a copy of ctx that marks the request as streamed.
+func withStream(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamContextKey{}, true)
+}
+
+// isStream returns true if the request of ctx is streamed.
+func isStream(ctx context.Context) bool {
+	stream, _ := ctx.Value(streamContextKey{}).(bool)
+	return stream
+}
+
+// getAuthorization returns the authorization of the request.
+// The authorization of the context takes precedence over an Authorization header given with the request,
+// which in turn takes precedence over the authorization of the Builder.
+// The authorization of the Builder is ignored when a TokenSource is configured.
+// It returns nil when the request must be sent without an authorization or with its own Authorization header.
+func (c *goHTTPClient) getAuthorization(ctx context.Context, headers http.Header) Authorization {
+	authorization := authorizationFromContext(ctx)
+	if authorization == nil {
+		if headers.Get("Authorization") != "" || c.builder.tokenSource != nil {
+			return nil
+		}
+		authorization = c.builder.authorization
+	}
+	if authorization == nil || !authorization.IsSet() {
+		return nil
+	}
+	return authorization
+}
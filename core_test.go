@@ -2,6 +2,7 @@ package go_requests
 
 import (
 	"net/http"
+	"reflect"
 	"testing"
 )
 
@@ -83,3 +84,25 @@ func Test_goHTTPClient_getHeaders(t *testing.T) {
 		})
 	}
 }
+
+func Test_goHTTPClient_getHeaders_precedence(t *testing.T) {
+	builder := NewBuilder()
+	builder.Headers().
+		Add("Accept", "application/json").
+		Add("Accept", "application/xml").
+		Add("Cookie", "a=1").
+		Add("Cookie", "b=2")
+	c := builder.Build().(*goHTTPClient)
+	got := c.getHeaders(http.Header{
+		"accept":       {"text/plain"},
+		"X-Request-Id": {"1", "2"},
+	})
+	want := http.Header{
+		"Accept":       {"text/plain"},
+		"Cookie":       {"a=1", "b=2"},
+		"X-Request-Id": {"1", "2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getHeaders() = %v, want %v", got, want)
+	}
+}
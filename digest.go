@@ -0,0 +1,234 @@
+package go_requests
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// digestChallenge is a WWW-Authenticate challenge of the digest scheme (RFC 7616 section 3.3).
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	// nc is the nonce count, the number of requests sent with the nonce
+	nc uint32
+}
+
+// digestAuth answers digest challenges and remembers the last challenge of every host,
+// so that subsequent requests are authorized without an extra round-trip.
+// The zero value is ready to use.
+type digestAuth struct {
+	mu         sync.Mutex
+	challenges map[string]*digestChallenge
+}
+
+// middleware returns the Middleware that negotiates digest authorization for the requests
+// whose context carries a digest Authorization.
+func (d *digestAuth) middleware() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			authorization := authorizationFromContext(req.Context())
+			if authorization == nil || !authorization.IsDigest() {
+				return next(req)
+			}
+			credentials, ok := authorization.(*authorizationImpl)
+			if !ok {
+				return next(req)
+			}
+			if header, ok := d.authorize(req, credentials.username, credentials.password); ok {
+				req.Header.Set("Authorization", header)
+			}
+			response, err := next(req)
+			if err != nil || response.StatusCode() != http.StatusUnauthorized {
+				return response, err
+			}
+			challenge := parseDigestChallenge(response.Header().Values("WWW-Authenticate"))
+			if challenge == nil || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+				return response, err
+			}
+			d.mu.Lock()
+			if d.challenges == nil {
+				d.challenges = make(map[string]*digestChallenge)
+			}
+			d.challenges[req.URL.Host] = challenge
+			d.mu.Unlock()
+
+			retry := req.Clone(req.Context())
+			if req.GetBody != nil {
+				if retry.Body, err = req.GetBody(); err != nil {
+					return response, nil
+				}
+			}
+			_ = response.Close()
+			header, _ := d.authorize(retry, credentials.username, credentials.password)
+			retry.Header.Set("Authorization", header)
+			return next(retry)
+		}
+	}
+}
+
+// authorize returns the Authorization header for the request computed from the last challenge of its host.
+// It returns false if the host has not sent a challenge yet.
+func (d *digestAuth) authorize(req *http.Request, username, password string) (string, bool) {
+	d.mu.Lock()
+	challenge, ok := d.challenges[req.URL.Host]
+	if !ok {
+		d.mu.Unlock()
+		return "", false
+	}
+	challenge.nc++
+	nc := challenge.nc
+	c := *challenge
+	d.mu.Unlock()
+	return c.authorization(req.Method, req.URL.RequestURI(), username, password, nc, newCnonce()), true
+}
+
+// authorization returns the value of the Authorization header that answers the challenge.
+func (c *digestChallenge) authorization(method, uri, username, password string, nc uint32, cnonce string) string {
+	h := digestHash(c.algorithm)
+	ha1 := h(username + ":" + c.realm + ":" + password)
+	if strings.HasSuffix(strings.ToUpper(c.algorithm), "-SESS") {
+		ha1 = h(ha1 + ":" + c.nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+	ncValue := fmt.Sprintf("%08x", nc)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `Digest username="%s", realm="%s", nonce="%s", uri="%s"`,
+		quoteEscape(username), quoteEscape(c.realm), quoteEscape(c.nonce), uri)
+	if c.algorithm != "" {
+		fmt.Fprintf(&b, ", algorithm=%s", c.algorithm)
+	}
+	if c.qop != "" {
+		response := h(ha1 + ":" + c.nonce + ":" + ncValue + ":" + cnonce + ":" + c.qop + ":" + ha2)
+		fmt.Fprintf(&b, `, response="%s", qop=%s, nc=%s, cnonce="%s"`, response, c.qop, ncValue, cnonce)
+	} else {
+		fmt.Fprintf(&b, `, response="%s"`, h(ha1+":"+c.nonce+":"+ha2))
+	}
+	if c.opaque != "" {
+		fmt.Fprintf(&b, `, opaque="%s"`, quoteEscape(c.opaque))
+	}
+	return b.String()
+}
+
+// parseDigestChallenge returns the strongest supported digest challenge among the WWW-Authenticate header values,
+// or nil if there is none. Only the "auth" quality of protection is supported.
+func parseDigestChallenge(values []string) *digestChallenge {
+	var best *digestChallenge
+	for _, value := range values {
+		scheme, rest, _ := strings.Cut(strings.TrimSpace(value), " ")
+		if !strings.EqualFold(scheme, string(AuthorizationTypeDigest)) {
+			continue
+		}
+		params := parseAuthParams(rest)
+		challenge := &digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+		}
+		if challenge.nonce == "" || digestHash(challenge.algorithm) == nil {
+			continue
+		}
+		if qop, ok := params["qop"]; ok {
+			for _, option := range strings.Split(qop, ",") {
+				if strings.TrimSpace(option) == "auth" {
+					challenge.qop = "auth"
+				}
+			}
+			if challenge.qop == "" {
+				continue
+			}
+		}
+		if best == nil || digestStrength(challenge.algorithm) > digestStrength(best.algorithm) {
+			best = challenge
+		}
+	}
+	return best
+}
+
+// parseAuthParams parses the comma separated auth-params of a challenge, e.g. realm="x", qop="auth,auth-int".
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return params
+		}
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return params
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " \t")
+		var value strings.Builder
+		if strings.HasPrefix(s, `"`) {
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				value.WriteByte(s[i])
+			}
+			if i < len(s) {
+				i++
+			}
+			s = s[i:]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			value.WriteString(strings.TrimSpace(s[:end]))
+			s = s[end:]
+		}
+		params[key] = value.String()
+	}
+}
+
+// digestHash returns the hex encoded hash function of the algorithm, or nil if the algorithm is not supported.
+func digestHash(algorithm string) func(string) string {
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return nil
+	}
+	return func(s string) string {
+		h := newHash()
+		h.Write([]byte(s))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+}
+
+// digestStrength ranks the supported algorithms so that SHA-256 is preferred over MD5.
+func digestStrength(algorithm string) int {
+	if strings.HasPrefix(strings.ToUpper(algorithm), "SHA-256") {
+		return 1
+	}
+	return 0
+}
+
+// newCnonce returns a random client nonce.
+func newCnonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// quoteEscape escapes the backslashes and double quotes of a quoted-string.
+func quoteEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,178 @@
+package go_requests
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_digestChallenge_authorization(t *testing.T) {
+	// test vectors from RFC 7616 section 3.9.1
+	const (
+		nonce  = "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v"
+		opaque = "FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS"
+		cnonce = "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ"
+	)
+	tests := []struct {
+		name      string
+		algorithm string
+		want      string
+	}{
+		{
+			name:      "MD5",
+			algorithm: "MD5",
+			want:      `response="8ca523f5e9506fed4657c9700eebdbec"`,
+		},
+		{
+			name:      "SHA-256",
+			algorithm: "SHA-256",
+			want:      `response="753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := parseDigestChallenge([]string{fmt.Sprintf(
+				`Digest realm="http-auth@example.org", qop="auth, auth-int", algorithm=%s, nonce="%s", opaque="%s"`,
+				tt.algorithm, nonce, opaque)})
+			if challenge == nil {
+				t.Fatalf("parseDigestChallenge() = nil")
+			}
+			got := challenge.authorization(http.MethodGet, "/dir/index.html", "Mufasa", "Circle of Life", 1, cnonce)
+			for _, want := range []string{tt.want, "qop=auth", "nc=00000001", `opaque="` + opaque + `"`} {
+				if !strings.Contains(got, want) {
+					t.Errorf("authorization() = %v, want it to contain %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_digestChallenge_authorization_quotes(t *testing.T) {
+	challenge := parseDigestChallenge([]string{
+		`Digest realm="api", qop="auth", nonce="n\", realm=\"evil", opaque="o\\\"x"`,
+	})
+	if challenge == nil {
+		t.Fatalf("parseDigestChallenge() = nil")
+	}
+	if challenge.nonce != `n", realm="evil` || challenge.opaque != `o\"x` {
+		t.Fatalf("challenge nonce, opaque = %q, %q", challenge.nonce, challenge.opaque)
+	}
+	got := challenge.authorization(http.MethodGet, "/", "user", "pass", 1, "cnonce")
+	params := parseAuthParams(strings.TrimPrefix(got, "Digest "))
+	if params["nonce"] != challenge.nonce || params["opaque"] != challenge.opaque || params["realm"] != "api" {
+		t.Errorf("authorization() = %v, want the nonce and opaque of the challenge as quoted-strings", got)
+	}
+}
+
+func Test_parseDigestChallenge(t *testing.T) {
+	tests := []struct {
+		name          string
+		values        []string
+		wantNil       bool
+		wantAlgorithm string
+		wantQop       string
+	}{
+		{
+			name:    "not digest",
+			values:  []string{`Basic realm="x"`},
+			wantNil: true,
+		},
+		{
+			name:    "unsupported algorithm",
+			values:  []string{`Digest realm="x", nonce="n", algorithm=SHA-512-256`},
+			wantNil: true,
+		},
+		{
+			name:    "unsupported qop",
+			values:  []string{`Digest realm="x", nonce="n", qop="auth-int"`},
+			wantNil: true,
+		},
+		{
+			name:          "legacy without qop",
+			values:        []string{`Digest realm="x", nonce="n"`},
+			wantAlgorithm: "",
+			wantQop:       "",
+		},
+		{
+			name: "strongest algorithm",
+			values: []string{
+				`Digest realm="x", nonce="n", qop="auth", algorithm=MD5`,
+				`Digest realm="x", nonce="n", qop="auth", algorithm=SHA-256`,
+			},
+			wantAlgorithm: "SHA-256",
+			wantQop:       "auth",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDigestChallenge(tt.values)
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("parseDigestChallenge() = %v, wantNil %v", got, tt.wantNil)
+			}
+			if got == nil {
+				return
+			}
+			if got.algorithm != tt.wantAlgorithm || got.qop != tt.wantQop {
+				t.Errorf("parseDigestChallenge() = %v/%v, want %v/%v", got.algorithm, got.qop, tt.wantAlgorithm, tt.wantQop)
+			}
+		})
+	}
+}
+
+func Test_goHTTPClient_Digest(t *testing.T) {
+	md5Hex := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	var challenges int
+	var counts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := parseAuthParams(strings.TrimPrefix(r.Header.Get("Authorization"), "Digest "))
+		ha1 := md5Hex("user:test@example.org:secret")
+		ha2 := md5Hex(r.Method + ":" + r.URL.RequestURI())
+		want := md5Hex(ha1 + ":server-nonce:" + params["nc"] + ":" + params["cnonce"] + ":auth:" + ha2)
+		if params["response"] != want || params["uri"] != r.URL.RequestURI() {
+			challenges++
+			w.Header().Set("WWW-Authenticate", `Digest realm="test@example.org", qop="auth", nonce="server-nonce", algorithm=MD5`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		counts = append(counts, params["nc"])
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	builder := NewBuilder()
+	builder.SetAuthorization(NewAuthorization().Digest("user", "secret"))
+	client := builder.Build()
+	for _, path := range []string{"/first?x=1", "/second"} {
+		res, err := client.Put(server.URL+path, []byte("payload"))
+		if err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+		if res.StatusCode() != http.StatusOK {
+			t.Errorf("Put(%v) status = %v, want %v", path, res.StatusCode(), http.StatusOK)
+		}
+	}
+	if challenges != 1 {
+		t.Errorf("challenges = %v, want 1", challenges)
+	}
+	if fmt.Sprint(counts) != "[00000001 00000002]" {
+		t.Errorf("nonce counts = %v, want [00000001 00000002]", counts)
+	}
+
+	// a wrong password is answered with the 401 of the server
+	ctx := WithAuthorization(context.Background(), NewAuthorization().Digest("user", "wrong"))
+	res, err := client.GetWithContext(ctx, server.URL+"/third")
+	if err != nil {
+		t.Fatalf("GetWithContext() error = %v", err)
+	}
+	if res.StatusCode() != http.StatusUnauthorized {
+		t.Errorf("GetWithContext() status = %v, want %v", res.StatusCode(), http.StatusUnauthorized)
+	}
+}
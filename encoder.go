@@ -0,0 +1,203 @@
+package go_requests
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// queryTag is the struct tag read by QueryParams.SetStruct
+	queryTag = "query"
+	// headerTag is the struct tag read by Headers.SetStruct
+	headerTag = "header"
+	// layoutTag is the struct tag that sets the layout of a time.Time field
+	layoutTag = "layout"
+)
+
+// Encoder is implemented by the types that encode themselves into query params or headers with SetStruct.
+// key is the name of the field in the tag. The values are added under key, so that a slice is sent as several values.
+type Encoder interface {
+	EncodeValues(key string) ([]string, error)
+}
+
+var (
+	encoderType       = reflect.TypeOf((*Encoder)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// structValues is the result of encodeStruct: the values by key, and the keys in the order of the fields
+type structValues struct {
+	keys   []string
+	values map[string][]string
+}
+
+// add adds values to the key.
+func (s *structValues) add(key string, values ...string) {
+	if _, ok := s.values[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = append(s.values[key], values...)
+}
+
+// encodeStruct encodes the exported fields of the struct v that have the tag tagName, e.g. `query:"name,omitempty"`.
+//   - The name of the tag is the key of the field. A name of "-" skips the field.
+//   - The omitempty option skips the zero values.
+//   - Slices and arrays are encoded as one value per element, empty ones are skipped.
+//   - Pointers are dereferenced, nil pointers are skipped.
+//   - time.Time is formatted with the layout of the `layout` tag, RFC 3339 by default. The unix option encodes
+//     the number of seconds since January 1, 1970 UTC instead.
+//   - Embedded structs without tag are flattened.
+//   - Types that implement Encoder or encoding.TextMarshaler encode themselves.
+//
+// Fields without the tag are ignored.
+func encodeStruct(v interface{}, tagName string) (*structValues, error) {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() {
+		return nil, fmt.Errorf("%s: cannot encode nil", tagName)
+	}
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, fmt.Errorf("%s: cannot encode a nil %s", tagName, value.Type())
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s: cannot encode %s, a struct is expected", tagName, value.Type())
+	}
+	result := &structValues{values: make(map[string][]string)}
+	if err := encodeFields(value, tagName, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// encodeFields encodes the fields of the struct value into result.
+func encodeFields(value reflect.Value, tagName string, result *structValues) error {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		tag, tagged := field.Tag.Lookup(tagName)
+		fieldValue := value.Field(i)
+		if !tagged {
+			if field.Anonymous {
+				if err := encodeEmbedded(fieldValue, tagName, result); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitEmpty, unix := false, false
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "omitempty":
+				omitEmpty = true
+			case "unix":
+				unix = true
+			}
+		}
+		if omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+		values, err := encodeField(fieldValue, name, field.Tag.Get(layoutTag), unix)
+		if err != nil {
+			return fmt.Errorf("%s: field %s: %w", tagName, field.Name, err)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		result.add(name, values...)
+	}
+	return nil
+}
+
+// encodeEmbedded flattens the fields of an embedded struct or pointer to struct.
+func encodeEmbedded(value reflect.Value, tagName string, result *structValues) error {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	return encodeFields(value, tagName, result)
+}
+
+// encodeField returns the values of a field.
+func encodeField(value reflect.Value, key, layout string, unix bool) ([]string, error) {
+	if value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, nil
+		}
+		if value.Type().Implements(encoderType) {
+			return value.Interface().(Encoder).EncodeValues(key)
+		}
+		return encodeField(value.Elem(), key, layout, unix)
+	}
+	if value.Type().Implements(encoderType) {
+		return value.Interface().(Encoder).EncodeValues(key)
+	}
+	if value.CanAddr() && value.Addr().Type().Implements(encoderType) {
+		return value.Addr().Interface().(Encoder).EncodeValues(key)
+	}
+	if value.Type() == timeType {
+		t := value.Interface().(time.Time)
+		if unix {
+			return []string{strconv.FormatInt(t.Unix(), 10)}, nil
+		}
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return []string{t.Format(layout)}, nil
+	}
+	if value.Type().Implements(textMarshalerType) {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		return []string{string(text)}, err
+	}
+	switch value.Kind() {
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return []string{string(value.Bytes())}, nil
+		}
+		fallthrough
+	case reflect.Array:
+		var values []string
+		for i := 0; i < value.Len(); i++ {
+			elem, err := encodeField(value.Index(i), key, layout, unix)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, elem...)
+		}
+		return values, nil
+	case reflect.String:
+		return []string{value.String()}, nil
+	case reflect.Bool:
+		return []string{strconv.FormatBool(value.Bool())}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return []string{strconv.FormatInt(value.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return []string{strconv.FormatUint(value.Uint(), 10)}, nil
+	case reflect.Float32:
+		return []string{strconv.FormatFloat(value.Float(), 'f', -1, 32)}, nil
+	case reflect.Float64:
+		return []string{strconv.FormatFloat(value.Float(), 'f', -1, 64)}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", value.Type())
+}
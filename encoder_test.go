@@ -0,0 +1,112 @@
+package go_requests
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// upperEncoder is an Encoder that sends its value in upper case under two keys
+type upperEncoder string
+
+func (e upperEncoder) EncodeValues(key string) ([]string, error) {
+	return []string{strings.ToUpper(string(e)), key}, nil
+}
+
+func Test_encodeStruct(t *testing.T) {
+	type Pagination struct {
+		Page int `query:"page"`
+	}
+	type options struct {
+		Pagination
+		Status   []string     `query:"status"`
+		Name     string       `query:"name,omitempty"`
+		Empty    string       `query:"empty"`
+		Limit    *int         `query:"limit"`
+		Offset   *int         `query:"offset"`
+		Since    time.Time    `query:"since" layout:"2006-01-02"`
+		Until    time.Time    `query:"until,unix"`
+		Created  time.Time    `query:"created"`
+		Custom   upperEncoder `query:"custom"`
+		Skipped  string       `query:"-"`
+		Untagged string
+		Ratio    float64 `query:"ratio"`
+		Active   bool    `query:"active"`
+	}
+	limit := 10
+	at := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	got, err := encodeStruct(&options{
+		Pagination: Pagination{Page: 2},
+		Status:     []string{"available", "sold"},
+		Limit:      &limit,
+		Since:      at,
+		Until:      at,
+		Created:    at,
+		Custom:     "abc",
+		Skipped:    "x",
+		Untagged:   "x",
+		Ratio:      0.5,
+		Active:     true,
+	}, queryTag)
+	if err != nil {
+		t.Fatalf("encodeStruct() error = %v", err)
+	}
+	want := &structValues{
+		keys: []string{"page", "status", "empty", "limit", "since", "until", "created", "custom", "ratio", "active"},
+		values: map[string][]string{
+			"page":    {"2"},
+			"status":  {"available", "sold"},
+			"empty":   {""},
+			"limit":   {"10"},
+			"since":   {"2023-04-05"},
+			"until":   {"1680674828"},
+			"created": {"2023-04-05T06:07:08Z"},
+			"custom":  {"ABC", "custom"},
+			"ratio":   {"0.5"},
+			"active":  {"true"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("encodeStruct() = %+v, want %+v", got, want)
+	}
+
+	for _, v := range []interface{}{nil, "not a struct", (*options)(nil), struct {
+		Nested struct{} `query:"nested"`
+	}{}} {
+		if _, err = encodeStruct(v, queryTag); err == nil {
+			t.Errorf("encodeStruct(%#v) expected an error", v)
+		}
+	}
+}
+
+func Test_SetStruct(t *testing.T) {
+	type tracing struct {
+		RequestID string   `header:"x-request-id" query:"request_id"`
+		Baggage   []string `header:"Baggage,omitempty"`
+	}
+	headers := NewHeaders().Set("X-Request-Id", "old").Set("Accept", "application/json")
+	if err := headers.SetStruct(tracing{RequestID: "abc", Baggage: []string{"a=1", "b=2"}}); err != nil {
+		t.Fatalf("Headers.SetStruct() error = %v", err)
+	}
+	wantHeaders := map[string][]string{
+		"Accept":       {"application/json"},
+		"X-Request-Id": {"abc"},
+		"Baggage":      {"a=1", "b=2"},
+	}
+	if got := headers.GetAll(); !reflect.DeepEqual(got, wantHeaders) {
+		t.Errorf("Headers.SetStruct() = %v, want %v", got, wantHeaders)
+	}
+
+	params := NewQueryParams().Set("request_id", "old").Set("page", "1")
+	if err := params.SetStruct(tracing{RequestID: "abc"}); err != nil {
+		t.Fatalf("QueryParams.SetStruct() error = %v", err)
+	}
+	if got := params.Encode(); got != "page=1&request_id=abc" {
+		t.Errorf("QueryParams.SetStruct() = %v", got)
+	}
+	if err := NewQueryParams().SetStruct(http.Header{}); err == nil {
+		t.Errorf("QueryParams.SetStruct() expected an error for a map")
+	}
+}
@@ -1,6 +1,9 @@
 package go_requests
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // ErrorContentType is the error type for content type errors
 type ErrorContentType error
@@ -10,3 +13,33 @@ func UnsupportedContentType() ErrorContentType { return errors.New("unsupported
 
 // NoContentType is the error type for no content type errors. It is returned when the content type is not set. This is the default error type.
 func NoContentType() ErrorContentType { return errors.New("no content type") }
+
+// StatusError is the error returned by the typed helpers, such as GetAs, when the server answers with a status code
+// other than 2xx. The body of the response is kept so that error payloads can still be decoded.
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response, e.g. 404.
+	StatusCode int
+	// Status is the HTTP status of the response, e.g. "404 Not Found".
+	Status string
+	// Body is the body of the response.
+	Body []byte
+}
+
+// Error returns the status of the response.
+func (e *StatusError) Error() string {
+	return "unexpected response status: " + e.Status
+}
+
+// ErrCircuitOpen is the error returned for the requests to a host whose circuit is open, see CircuitBreaker.
+// The requests are not sent until the cool-down of the circuit ends.
+type ErrCircuitOpen struct {
+	// Host is the host of the request, with its port if any.
+	Host string
+	// Until is when the circuit lets a request through again to probe the host.
+	Until time.Time
+}
+
+// Error returns the host whose circuit is open.
+func (e *ErrCircuitOpen) Error() string {
+	return "circuit open for host " + e.Host + " until " + e.Until.Format(time.RFC3339)
+}
@@ -32,6 +32,7 @@ func getCustomClient() go_requests.Client {
 		SetContentType("application/json").
 		SetAccept("application/json").SetUserAgent("go-requests")
 	builder.SetHTTPClient(customClient)
+	builder.SetBaseURL(baseURL)
 	return builder.Build()
 }
 
@@ -45,6 +46,7 @@ func getClient(contentType string) go_requests.Client {
 	builder.SetRequestTimeout(10 * time.Second).
 		SetResponseTimeout(10 * time.Second).
 		SetMaxIdleConnections(10)
+	builder.SetBaseURL(baseURL)
 
 	return builder.Build()
 }
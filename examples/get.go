@@ -1,13 +1,10 @@
 package examples
 
+import "github.com/cploutarchou/go-requests"
+
 func findPetsByTagJSON(tag string) (PetsTags, error) {
 	jsonContentClient.QueryParams().Set("tags", tag)
-	resp, err := jsonContentClient.Get(baseURL + "/pet/findByTags")
-	if err != nil {
-		return nil, err
-	}
-	var pets PetsTags
-	err = resp.Unmarshal(&pets)
+	pets, _, err := go_requests.GetAs[PetsTags](jsonContentClient, "/pet/findByTags")
 	if err != nil {
 		return nil, err
 	}
@@ -16,12 +13,7 @@ func findPetsByTagJSON(tag string) (PetsTags, error) {
 
 func findPetsByTagXML(tag string) (PetsTags, error) {
 	xmlContentClient.QueryParams().Set("tags", tag)
-	resp, err := xmlContentClient.Get(baseURL + "/pet/findByTags")
-	if err != nil {
-		return nil, err
-	}
-	var pets PetsTags
-	err = resp.Unmarshal(&pets)
+	pets, _, err := go_requests.GetAs[PetsTags](xmlContentClient, "/pet/findByTags")
 	if err != nil {
 		return nil, err
 	}
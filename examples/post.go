@@ -1,18 +1,9 @@
 package examples
 
-import "encoding/json"
+import "github.com/cploutarchou/go-requests"
 
 func placePetOrder(item Order) (*Order, error) {
-	data, err := json.Marshal(item)
-	if err != nil {
-		return nil, err
-	}
-	res, err := jsonContentClient.Post(baseURL+"/store/order", data)
-	if err != nil {
-		return nil, err
-	}
-	var order Order
-	err = res.Unmarshal(&order)
+	order, _, err := go_requests.PostAs[Order](jsonContentClient, "/store/order", item)
 	if err != nil {
 		return nil, err
 	}
@@ -1,20 +1,9 @@
 package examples
 
-import (
-	"encoding/json"
-)
+import "github.com/cploutarchou/go-requests"
 
 func updatePet(item *Pet) (*updateRes, error) {
-	data, err := json.Marshal(item)
-	if err != nil {
-		return nil, err
-	}
-	res, err := jsonContentClient.Put(baseURL+"/pet", data)
-	if err != nil {
-		return nil, err
-	}
-	var response updateRes
-	err = res.Unmarshal(&response)
+	response, _, err := go_requests.PutAs[updateRes](jsonContentClient, "/pet", item)
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // HeaderType is the type of the header. It is used to set the header value to the header object using the Set method.
@@ -53,8 +54,10 @@ const (
 
 // Headers is the interface for the http headers object of the http package of the standard library of Go (golang)
 type Headers interface {
-	// Set sets a header to the header
+	// Set sets a header to the header, replacing its values
 	Set(key, value string) Headers
+	// Add adds a value to the header, keeping its values
+	Add(key, value string) Headers
 	// SetContentType sets the content type to the header
 	SetContentType(contentType string) Headers
 	// SetContentLength sets the content length to the header
@@ -107,8 +110,8 @@ type Headers interface {
 	IsSet() bool
 	// String returns the string representation of the header
 	String() string
-	// Values returns the values of the header
-	Values() map[string]string
+	// Values returns all the values of the header
+	Values(key string) []string
 	// Keys returns the keys of the header
 	Keys() []string
 	// Len returns the length of the header
@@ -119,11 +122,15 @@ type Headers interface {
 	GetAllHttpHeaders() http.Header
 	//SetUserAgent sets the user agent to the header.
 	SetUserAgent(userAgent string)
+	// SetStruct sets the fields of the struct v that have a header tag, e.g. `header:"X-Request-Id"`,
+	// replacing the values of their headers. Slices are added as several values.
+	SetStruct(v interface{}) error
 }
 
 // headerImpl is the implementation of the Headers interface
+// The keys are canonicalized, so that content-type and Content-Type are the same header.
 type headerImpl struct {
-	values map[string]string
+	values http.Header
 }
 
 // Set sets a header to the header object
@@ -137,133 +144,146 @@ type headerImpl struct {
 //	header.Set("Content-Type", "application/json")
 //	header.Set("Content-Length", "100")
 func (h *headerImpl) Set(key, value string) Headers {
-	h.values[key] = value
+	h.values.Set(key, value)
+	return h
+}
+
+// Add adds a value to the values of the header.
+// Unlike Set, it keeps the values already set, e.g. to send several Accept or Cookie headers.
+//
+// Example:
+//
+//	header := NewHeaders()
+//	header.Add("Accept", "application/json")
+//	header.Add("Accept", "application/xml")
+func (h *headerImpl) Add(key, value string) Headers {
+	h.values.Add(key, value)
 	return h
 }
 
 // SetContentType sets the content type to the header object
 func (h *headerImpl) SetContentType(contentType string) Headers {
-	h.values[string(HeaderTypeContentType)] = contentType
+	h.values.Set(string(HeaderTypeContentType), contentType)
 	return h
 }
 
 // SetContentLength sets the content length to the header object
 func (h *headerImpl) SetContentLength(contentLength int) Headers {
-	h.values[string(HeaderTypeContentLength)] = strconv.Itoa(contentLength)
+	h.values.Set(string(HeaderTypeContentLength), strconv.Itoa(contentLength))
 	return h
 }
 
 // SetContentDisposition sets the content disposition to the header object
 func (h *headerImpl) SetContentDisposition(contentDisposition string) Headers {
-	h.values[string(HeaderTypeContentDisposition)] = contentDisposition
+	h.values.Set(string(HeaderTypeContentDisposition), contentDisposition)
 	return h
 }
 
 // SetContentEncoding sets the content encoding to the header object
 func (h *headerImpl) SetContentEncoding(contentEncoding string) Headers {
-	h.values[string(HeaderTypeContentEncoding)] = contentEncoding
+	h.values.Set(string(HeaderTypeContentEncoding), contentEncoding)
 	return h
 }
 
 // SetContentLanguage sets the content language to the header object
 func (h *headerImpl) SetContentLanguage(contentLanguage string) Headers {
-	h.values[string(HeaderTypeContentLanguage)] = contentLanguage
+	h.values.Set(string(HeaderTypeContentLanguage), contentLanguage)
 	return h
 }
 
 // SetContentLocation sets the content location to the header object
 func (h *headerImpl) SetContentLocation(contentLocation string) Headers {
-	h.values[string(HeaderTypeContentLocation)] = contentLocation
+	h.values.Set(string(HeaderTypeContentLocation), contentLocation)
 	return h
 }
 
 // SetContentMD5 sets the content md5 to the header object
 func (h *headerImpl) SetContentMD5(contentMD5 string) Headers {
-	h.values[string(HeaderTypeContentMD5)] = contentMD5
+	h.values.Set(string(HeaderTypeContentMD5), contentMD5)
 	return h
 }
 
 // SetContentRange sets the content range to the header object. The content range is a string in the format "bytes 0-100/1000"
 func (h *headerImpl) SetContentRange(contentRange string) Headers {
-	h.values[string(HeaderTypeContentRange)] = contentRange
+	h.values.Set(string(HeaderTypeContentRange), contentRange)
 	return h
 }
 
 // SetCookie sets the cookie to the header object
 func (h *headerImpl) SetCookie(cookie string) Headers {
-	h.values[string(HeaderTypeCookie)] = cookie
+	h.values.Set(string(HeaderTypeCookie), cookie)
 	return h
 }
 
 // SetDate sets the date to the header object
 func (h *headerImpl) SetDate(date string) Headers {
-	h.values[string(HeaderTypeDate)] = date
+	h.values.Set(string(HeaderTypeDate), date)
 	return h
 }
 
 // SetETag sets the etag to the header object
 func (h *headerImpl) SetETag(etag string) Headers {
-	h.values[string(HeaderTypeETag)] = etag
+	h.values.Set(string(HeaderTypeETag), etag)
 	return h
 }
 
 // SetExpires sets the expires to the header object
 func (h *headerImpl) SetExpires(expires string) Headers {
-	h.values[string(HeaderTypeExpires)] = expires
+	h.values.Set(string(HeaderTypeExpires), expires)
 	return h
 }
 
 // SetAccept sets to accept to the header object
 func (h *headerImpl) SetAccept(accept string) Headers {
-	h.values[string(HeaderTypeAccept)] = accept
+	h.values.Set(string(HeaderTypeAccept), accept)
 	return h
 }
 
 // SetAcceptCharset sets to accept charset to the header object
 func (h *headerImpl) SetAcceptCharset(acceptCharset string) Headers {
-	h.values[string(HeaderTypeAcceptCharset)] = acceptCharset
+	h.values.Set(string(HeaderTypeAcceptCharset), acceptCharset)
 	return h
 }
 
 // SetAcceptEncoding sets to accept encoding to the header object
 func (h *headerImpl) SetAcceptEncoding(acceptEncoding string) Headers {
-	h.values[string(HeaderTypeAcceptEncoding)] = acceptEncoding
+	h.values.Set(string(HeaderTypeAcceptEncoding), acceptEncoding)
 	return h
 }
 
 // SetAcceptLanguage sets to accept language to the header object
 func (h *headerImpl) SetAcceptLanguage(acceptLanguage string) Headers {
-	h.values[string(HeaderTypeAcceptLanguage)] = acceptLanguage
+	h.values.Set(string(HeaderTypeAcceptLanguage), acceptLanguage)
 	return h
 }
 
 // SetAcceptRanges sets to accept ranges to the header object
 func (h *headerImpl) SetAcceptRanges(acceptRanges string) Headers {
-	h.values[string(HeaderTypeAcceptRanges)] = acceptRanges
+	h.values.Set(string(HeaderTypeAcceptRanges), acceptRanges)
 	return h
 }
 
 // SetAge sets the age to the header object
 func (h *headerImpl) SetAge(age string) Headers {
-	h.values[string(HeaderTypeAge)] = age
+	h.values.Set(string(HeaderTypeAge), age)
 	return h
 }
 
 // SetAllow sets to allow to the header object
 func (h *headerImpl) SetAllow(allow string) Headers {
-	h.values[string(HeaderTypeAllow)] = allow
+	h.values.Set(string(HeaderTypeAllow), allow)
 	return h
 }
 
 // SetCustom sets a custom header to the header object
 func (h *headerImpl) SetCustom(key, value string) Headers {
-	h.values[key] = value
+	h.values.Set(key, value)
 	return h
 }
 
-// Get returns the value of the header
+// Get returns the first value of the header. The key is case-insensitive.
 func (h *headerImpl) Get(key string) string {
-	return h.values[key]
+	return h.values.Get(key)
 }
 
 // SetUserAgent sets the user agent to the header. If the user agent is empty, it will be set to the default user agent.
@@ -273,26 +293,18 @@ func (h *headerImpl) SetUserAgent(s string) {
 
 // GetAllHttpHeaders returns all http headers as http.Header object
 func (h *headerImpl) GetAllHttpHeaders() http.Header {
-	headers := http.Header{}
-	for k, v := range h.values {
-		headers.Set(k, v)
-	}
-	return headers
+	return h.values.Clone()
 }
 
 // Del deletes a header from the header object
 func (h *headerImpl) Del(key string) Headers {
-	delete(h.values, key)
+	h.values.Del(key)
 	return h
 }
 
 // Clone clones the header object
 func (h *headerImpl) Clone() Headers {
-	clone := make(map[string]string)
-	for k, v := range h.values {
-		clone[k] = v
-	}
-	return &headerImpl{values: clone}
+	return &headerImpl{values: h.values.Clone()}
 }
 
 // IsEmpty checks if the header object is empty
@@ -310,14 +322,14 @@ func (h *headerImpl) IsSet() bool {
 func (h *headerImpl) String() string {
 	var buffer bytes.Buffer
 	for k, v := range h.values {
-		buffer.WriteString(fmt.Sprintf("%s: %s", k, v))
+		buffer.WriteString(fmt.Sprintf("%s: %s", k, strings.Join(v, ", ")))
 	}
 	return buffer.String()
 }
 
-// Values returns the values of the header object
-func (h *headerImpl) Values() map[string]string {
-	return h.values
+// Values returns all the values of the header. The key is case-insensitive.
+func (h *headerImpl) Values(key string) []string {
+	return h.values.Values(key)
 }
 
 // Keys returns the keys of the header object
@@ -338,14 +350,37 @@ func (h *headerImpl) Len() int {
 func (h *headerImpl) GetAll() map[string][]string {
 	all := make(map[string][]string)
 	for k, v := range h.values {
-		all[k] = []string{v}
+		all[k] = append([]string(nil), v...)
 	}
 	return all
 }
 
+// SetStruct sets the tagged fields of the struct v to the header object
+//
+// Example:
+//
+//	type Tracing struct {
+//		RequestID string   `header:"X-Request-Id"`
+//		Baggage   []string `header:"Baggage,omitempty"`
+//	}
+//	err := header.SetStruct(Tracing{RequestID: "abc"})
+func (h *headerImpl) SetStruct(v interface{}) error {
+	encoded, err := encodeStruct(v, headerTag)
+	if err != nil {
+		return err
+	}
+	for _, key := range encoded.keys {
+		h.values.Del(key)
+		for _, value := range encoded.values[key] {
+			h.values.Add(key, value)
+		}
+	}
+	return nil
+}
+
 // NewHeaders returns a new header object
 func NewHeaders() Headers {
-	return &headerImpl{values: make(map[string]string)}
+	return &headerImpl{values: make(http.Header)}
 }
 
 // getHeader returns the header object of the  ...http.Header object ad http.Header object.
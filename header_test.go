@@ -8,7 +8,7 @@ import (
 
 func Test_headerImpl_Set(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		key   string
@@ -23,8 +23,8 @@ func Test_headerImpl_Set(t *testing.T) {
 		{
 			name: "Test Set",
 			fields: fields{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
 			args: args{
@@ -32,8 +32,8 @@ func Test_headerImpl_Set(t *testing.T) {
 				value: "value",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
 		},
@@ -52,7 +52,7 @@ func Test_headerImpl_Set(t *testing.T) {
 
 func Test_headerImpl_SetContentLength(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		contentLength int
@@ -66,14 +66,14 @@ func Test_headerImpl_SetContentLength(t *testing.T) {
 		{
 			name: "Test SetContentLength",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				contentLength: 10,
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Content-Length": "10",
+				values: http.Header{
+					"Content-Length": {"10"},
 				},
 			},
 		},
@@ -93,7 +93,7 @@ func Test_headerImpl_SetContentLength(t *testing.T) {
 
 func Test_headerImpl_SetContentDisposition(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		contentDisposition string
@@ -108,14 +108,14 @@ func Test_headerImpl_SetContentDisposition(t *testing.T) {
 		{
 			name: "Test SetContentDisposition",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				contentDisposition: "attachment",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Content-Disposition": "attachment",
+				values: http.Header{
+					"Content-Disposition": {"attachment"},
 				},
 			},
 		},
@@ -140,7 +140,7 @@ func TestNewHeaders(t *testing.T) {
 		{
 			name: "Test NewHeaders",
 			want: &headerImpl{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 		},
 	}
@@ -155,7 +155,7 @@ func TestNewHeaders(t *testing.T) {
 
 func Test_headerImpl_Clone(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	tests := []struct {
 		name   string
@@ -165,13 +165,13 @@ func Test_headerImpl_Clone(t *testing.T) {
 		{
 			name: "Test Clone",
 			fields: fields{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
 		},
@@ -190,7 +190,7 @@ func Test_headerImpl_Clone(t *testing.T) {
 
 func Test_headerImpl_Del(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		key string
@@ -204,15 +204,15 @@ func Test_headerImpl_Del(t *testing.T) {
 		{
 			name: "Test Del",
 			fields: fields{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
 			args: args{
 				key: "key",
 			},
 			want: &headerImpl{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 		},
 	}
@@ -230,7 +230,7 @@ func Test_headerImpl_Del(t *testing.T) {
 
 func Test_headerImpl_Get(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		key string
@@ -244,8 +244,8 @@ func Test_headerImpl_Get(t *testing.T) {
 		{
 			name: "Test Get",
 			fields: fields{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
 			args: args{
@@ -268,7 +268,7 @@ func Test_headerImpl_Get(t *testing.T) {
 
 func Test_headerImpl_GetAll(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	tests := []struct {
 		name   string
@@ -278,14 +278,14 @@ func Test_headerImpl_GetAll(t *testing.T) {
 		{
 			name: "Test GetAll",
 			fields: fields{
-				values: map[string]string{
-					"key":  "value",
-					"key2": "value2",
+				values: http.Header{
+					"Key":  {"value"},
+					"Key2": {"value2"},
 				},
 			},
 			want: map[string][]string{
-				"key":  {"value"},
-				"key2": {"value2"},
+				"Key":  {"value"},
+				"Key2": {"value2"},
 			},
 		},
 	}
@@ -303,7 +303,7 @@ func Test_headerImpl_GetAll(t *testing.T) {
 
 func Test_headerImpl_GetAllHttpHeaders(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	tests := []struct {
 		name   string
@@ -313,8 +313,8 @@ func Test_headerImpl_GetAllHttpHeaders(t *testing.T) {
 		{
 			name: "Test GetAllHttpHeaders",
 			fields: fields{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
 			want: http.Header{
@@ -336,7 +336,7 @@ func Test_headerImpl_GetAllHttpHeaders(t *testing.T) {
 
 func Test_headerImpl_IsEmpty(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	tests := []struct {
 		name   string
@@ -346,7 +346,7 @@ func Test_headerImpl_IsEmpty(t *testing.T) {
 		{
 			name: "Test IsEmpty",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			want: true,
 		},
@@ -365,7 +365,7 @@ func Test_headerImpl_IsEmpty(t *testing.T) {
 
 func Test_headerImpl_IsSet(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	tests := []struct {
 		name   string
@@ -375,8 +375,8 @@ func Test_headerImpl_IsSet(t *testing.T) {
 		{
 			name: "Test IsSet",
 			fields: fields{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
 			want: true,
@@ -396,7 +396,7 @@ func Test_headerImpl_IsSet(t *testing.T) {
 
 func Test_headerImpl_Keys(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	tests := []struct {
 		name   string
@@ -406,11 +406,11 @@ func Test_headerImpl_Keys(t *testing.T) {
 		{
 			name: "Test Keys",
 			fields: fields{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
-			want: []string{"key"},
+			want: []string{"Key"},
 		},
 	}
 	for _, tt := range tests {
@@ -427,7 +427,7 @@ func Test_headerImpl_Keys(t *testing.T) {
 
 func Test_headerImpl_Len(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	tests := []struct {
 		name   string
@@ -437,8 +437,8 @@ func Test_headerImpl_Len(t *testing.T) {
 		{
 			name: "Test Len",
 			fields: fields{
-				values: map[string]string{
-					"key": "value",
+				values: http.Header{
+					"Key": {"value"},
 				},
 			},
 			want: 1,
@@ -458,7 +458,7 @@ func Test_headerImpl_Len(t *testing.T) {
 
 func Test_headerImpl_SetAccept(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		accept string
@@ -472,14 +472,14 @@ func Test_headerImpl_SetAccept(t *testing.T) {
 		{
 			name: "Test SetAccept",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				accept: "application/json",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Accept": "application/json",
+				values: http.Header{
+					"Accept": {"application/json"},
 				},
 			},
 		},
@@ -498,7 +498,7 @@ func Test_headerImpl_SetAccept(t *testing.T) {
 
 func Test_headerImpl_SetAcceptCharset(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		acceptCharset string
@@ -512,15 +512,15 @@ func Test_headerImpl_SetAcceptCharset(t *testing.T) {
 		{
 			name: "Test SetAcceptCharset",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 
 			args: args{
 				acceptCharset: "utf-8",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Accept-Charset": "utf-8",
+				values: http.Header{
+					"Accept-Charset": {"utf-8"},
 				},
 			},
 		},
@@ -539,7 +539,7 @@ func Test_headerImpl_SetAcceptCharset(t *testing.T) {
 
 func Test_headerImpl_SetContentEncoding(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		contentEncoding string
@@ -553,14 +553,14 @@ func Test_headerImpl_SetContentEncoding(t *testing.T) {
 		{
 			name: "Test SetContentEncoding",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				contentEncoding: "gzip",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Content-Encoding": "gzip",
+				values: http.Header{
+					"Content-Encoding": {"gzip"},
 				},
 			},
 		},
@@ -579,7 +579,7 @@ func Test_headerImpl_SetContentEncoding(t *testing.T) {
 
 func Test_headerImpl_SetContentLanguage(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		contentLanguage string
@@ -593,14 +593,14 @@ func Test_headerImpl_SetContentLanguage(t *testing.T) {
 		{
 			name: "Test SetContentLanguage",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				contentLanguage: "en",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Content-Language": "en",
+				values: http.Header{
+					"Content-Language": {"en"},
 				},
 			},
 		},
@@ -619,7 +619,7 @@ func Test_headerImpl_SetContentLanguage(t *testing.T) {
 
 func Test_headerImpl_SetContentLocation(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		contentLocation string
@@ -633,14 +633,14 @@ func Test_headerImpl_SetContentLocation(t *testing.T) {
 		{
 			name: "Test SetContentLocation",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				contentLocation: "https://www.example.com",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Content-Location": "https://www.example.com",
+				values: http.Header{
+					"Content-Location": {"https://www.example.com"},
 				},
 			},
 		},
@@ -659,7 +659,7 @@ func Test_headerImpl_SetContentLocation(t *testing.T) {
 
 func Test_headerImpl_SetContentMD5(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		contentMD5 string
@@ -673,14 +673,14 @@ func Test_headerImpl_SetContentMD5(t *testing.T) {
 		{
 			name: "Test SetContentMD5",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				contentMD5: "Q2hlY2sgSW50ZWdyaXR5IQ==",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Content-MD5": "Q2hlY2sgSW50ZWdyaXR5IQ==",
+				values: http.Header{
+					"Content-Md5": {"Q2hlY2sgSW50ZWdyaXR5IQ=="},
 				},
 			},
 		},
@@ -699,7 +699,7 @@ func Test_headerImpl_SetContentMD5(t *testing.T) {
 
 func Test_headerImpl_SetContentRange(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		contentRange string
@@ -713,14 +713,14 @@ func Test_headerImpl_SetContentRange(t *testing.T) {
 		{
 			name: "Test SetContentRange",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				contentRange: "bytes 200-1000/67589",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Content-Range": "bytes 200-1000/67589",
+				values: http.Header{
+					"Content-Range": {"bytes 200-1000/67589"},
 				},
 			},
 		},
@@ -739,7 +739,7 @@ func Test_headerImpl_SetContentRange(t *testing.T) {
 
 func Test_headerImpl_SetCookie(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		cookie string
@@ -753,14 +753,14 @@ func Test_headerImpl_SetCookie(t *testing.T) {
 		{
 			name: "Test SetCookie",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				cookie: "theme=light;Token=abc123",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Cookie": "theme=light;Token=abc123",
+				values: http.Header{
+					"Cookie": {"theme=light;Token=abc123"},
 				},
 			},
 		},
@@ -779,7 +779,7 @@ func Test_headerImpl_SetCookie(t *testing.T) {
 
 func Test_headerImpl_SetDate(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		date string
@@ -793,14 +793,14 @@ func Test_headerImpl_SetDate(t *testing.T) {
 		{
 			name: "Test SetDate",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				date: "Tue, 15 Nov 1994 08:12:31 GMT",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Date": "Tue, 15 Nov 1994 08:12:31 GMT",
+				values: http.Header{
+					"Date": {"Tue, 15 Nov 1994 08:12:31 GMT"},
 				},
 			},
 		},
@@ -819,7 +819,7 @@ func Test_headerImpl_SetDate(t *testing.T) {
 
 func Test_headerImpl_SetETag(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		etag string
@@ -833,14 +833,14 @@ func Test_headerImpl_SetETag(t *testing.T) {
 		{
 			name: "Test SetETag",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				etag: "737060cd8c284d8af7ad3082f209582d",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"ETag": "737060cd8c284d8af7ad3082f209582d",
+				values: http.Header{
+					"Etag": {"737060cd8c284d8af7ad3082f209582d"},
 				},
 			},
 		},
@@ -859,7 +859,7 @@ func Test_headerImpl_SetETag(t *testing.T) {
 
 func Test_headerImpl_SetExpires(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		expires string
@@ -873,14 +873,14 @@ func Test_headerImpl_SetExpires(t *testing.T) {
 		{
 			name: "Test SetExpires",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				expires: "Thu, 22 Dec 2022 16:00:00 GMT",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Expires": "Thu, 22 Dec 2022 16:00:00 GMT",
+				values: http.Header{
+					"Expires": {"Thu, 22 Dec 2022 16:00:00 GMT"},
 				},
 			},
 		},
@@ -899,7 +899,7 @@ func Test_headerImpl_SetExpires(t *testing.T) {
 
 func Test_headerImpl_SetAcceptLanguage(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		acceptLanguage string
@@ -913,14 +913,14 @@ func Test_headerImpl_SetAcceptLanguage(t *testing.T) {
 		{
 			name: "Test SetAcceptLanguage",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				acceptLanguage: "en-US",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Accept-Language": "en-US",
+				values: http.Header{
+					"Accept-Language": {"en-US"},
 				},
 			},
 		},
@@ -940,7 +940,7 @@ func Test_headerImpl_SetAcceptLanguage(t *testing.T) {
 
 func Test_headerImpl_SetAcceptRanges(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		acceptRanges string
@@ -954,14 +954,14 @@ func Test_headerImpl_SetAcceptRanges(t *testing.T) {
 		{
 			name: "Test SetAcceptRanges",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				acceptRanges: "bytes",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Accept-Ranges": "bytes",
+				values: http.Header{
+					"Accept-Ranges": {"bytes"},
 				},
 			},
 		},
@@ -980,7 +980,7 @@ func Test_headerImpl_SetAcceptRanges(t *testing.T) {
 
 func Test_headerImpl_SetAge(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		age string
@@ -994,14 +994,14 @@ func Test_headerImpl_SetAge(t *testing.T) {
 		{
 			name: "Test SetAge",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				age: "12",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Age": "12",
+				values: http.Header{
+					"Age": {"12"},
 				},
 			},
 		},
@@ -1020,7 +1020,7 @@ func Test_headerImpl_SetAge(t *testing.T) {
 
 func Test_headerImpl_SetAllow(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
 	}
 	type args struct {
 		allow string
@@ -1034,14 +1034,14 @@ func Test_headerImpl_SetAllow(t *testing.T) {
 		{
 			name: "Test SetAllow",
 			fields: fields{
-				values: map[string]string{},
+				values: http.Header{},
 			},
 			args: args{
 				allow: "GET, HEAD",
 			},
 			want: &headerImpl{
-				values: map[string]string{
-					"Allow": "GET, HEAD",
+				values: http.Header{
+					"Allow": {"GET, HEAD"},
 				},
 			},
 		},
@@ -1061,23 +1061,28 @@ func Test_headerImpl_SetAllow(t *testing.T) {
 
 func Test_headerImpl_Values(t *testing.T) {
 	type fields struct {
-		values map[string]string
+		values http.Header
+	}
+	type args struct {
+		key string
 	}
 	tests := []struct {
 		name   string
 		fields fields
-		want   map[string]string
+		args   args
+		want   []string
 	}{
 		{
 			name: "Test Values",
 			fields: fields{
-				values: map[string]string{
-					"Accept": "application/json",
+				values: http.Header{
+					"Accept": {"application/json", "application/xml"},
 				},
 			},
-			want: map[string]string{
-				"Accept": "application/json",
+			args: args{
+				key: "accept",
 			},
+			want: []string{"application/json", "application/xml"},
 		},
 	}
 
@@ -1086,9 +1091,26 @@ func Test_headerImpl_Values(t *testing.T) {
 			h := &headerImpl{
 				values: tt.fields.values,
 			}
-			if got := h.Values(); !reflect.DeepEqual(got, tt.want) {
+			if got := h.Values(tt.args.key); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Values() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_headerImpl_Add(t *testing.T) {
+	h := NewHeaders().
+		Set("accept", "application/json").
+		Add("Accept", "application/xml").
+		Add("cookie", "a=1")
+	want := map[string][]string{
+		"Accept": {"application/json", "application/xml"},
+		"Cookie": {"a=1"},
+	}
+	if got := h.GetAll(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll() = %v, want %v", got, want)
+	}
+	if h.Set("ACCEPT", "text/plain").Get("Accept") != "text/plain" || h.Len() != 2 {
+		t.Errorf("Set() = %v", h.GetAll())
+	}
+}
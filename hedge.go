@@ -0,0 +1,281 @@
+package go_requests
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultHedgeMaxRequests is the default number of requests sent for a hedged request, including the first one
+	defaultHedgeMaxRequests = 2
+	// hedgeLatencySamples is the number of latencies a HedgePolicy keeps to compute its percentile
+	hedgeLatencySamples = 256
+)
+
+// HedgePolicy sends additional identical requests when a request has not been answered within a delay,
+// takes whichever response arrives first and cancels the other requests, to cut the tail latency of slow servers.
+// Only the safe methods (GET, HEAD, OPTIONS and TRACE) are hedged.
+//
+// A HedgePolicy is attached to every request of a client with Builder.SetHedgePolicy or to a single request with
+// Request.SetHedgePolicy. Hedging happens in the transport, below the retries and the middlewares, so that a
+// hedged request counts as a single attempt. Every hedge still takes a token from the RateLimiter of the Builder.
+//
+//	Example:
+//		builder.SetHedgePolicy(go_requests.NewHedgePolicy(50 * time.Millisecond).
+//			SetPercentile(0.95, 20).
+//			SetMaxRequests(3))
+type HedgePolicy interface {
+	// SetDelay sets how long to wait for a response before sending another request.
+	SetDelay(delay time.Duration) HedgePolicy
+	// SetPercentile makes the delay follow the latencies of the hedged requests: once minSamples latencies have been
+	// observed, the delay is their percentile (0 to 1), e.g. 0.95. Zero disables it and uses the fixed delay only.
+	SetPercentile(percentile float64, minSamples int) HedgePolicy
+	// SetMaxRequests sets how many requests may be sent for a hedged request, including the first one.
+	// Values lower than 2 disable hedging.
+	SetMaxRequests(requests int) HedgePolicy
+	// GetMaxRequests returns how many requests may be sent for a hedged request, including the first one.
+	GetMaxRequests() int
+	// Delay returns how long to wait for a response before sending another request.
+	Delay() time.Duration
+	// Observe records the latency of a request, used to compute the percentile delay.
+	Observe(latency time.Duration)
+}
+
+// hedgePolicyImpl is the default implementation of the HedgePolicy interface
+type hedgePolicyImpl struct {
+	mu          sync.Mutex
+	delay       time.Duration
+	percentile  float64
+	minSamples  int
+	maxRequests int
+	// latencies is a ring buffer of the last observed latencies, next the index of the next one
+	latencies []time.Duration
+	next      int
+}
+
+// NewHedgePolicy returns a new HedgePolicy that sends a second request if the first one has not been answered
+// within delay.
+func NewHedgePolicy(delay time.Duration) HedgePolicy {
+	return &hedgePolicyImpl{
+		delay:       delay,
+		maxRequests: defaultHedgeMaxRequests,
+	}
+}
+
+// SetDelay sets how long to wait for a response before sending another request.
+func (p *hedgePolicyImpl) SetDelay(delay time.Duration) HedgePolicy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.delay = delay
+	return p
+}
+
+// SetPercentile makes the delay follow the latencies of the hedged requests.
+func (p *hedgePolicyImpl) SetPercentile(percentile float64, minSamples int) HedgePolicy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if minSamples < 1 {
+		minSamples = 1
+	}
+	p.percentile = math.Min(math.Max(percentile, 0), 1)
+	p.minSamples = minSamples
+	return p
+}
+
+// SetMaxRequests sets how many requests may be sent for a hedged request.
+func (p *hedgePolicyImpl) SetMaxRequests(requests int) HedgePolicy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxRequests = requests
+	return p
+}
+
+// GetMaxRequests returns how many requests may be sent for a hedged request.
+func (p *hedgePolicyImpl) GetMaxRequests() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.maxRequests
+}
+
+// Delay returns the percentile of the observed latencies once there are enough of them, the fixed delay otherwise.
+func (p *hedgePolicyImpl) Delay() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.percentile <= 0 || len(p.latencies) < p.minSamples {
+		return p.delay
+	}
+	sorted := append([]time.Duration(nil), p.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(math.Ceil(p.percentile*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
+
+// Observe records the latency of a request.
+func (p *hedgePolicyImpl) Observe(latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.latencies) < hedgeLatencySamples {
+		p.latencies = append(p.latencies, latency)
+		return
+	}
+	p.latencies[p.next] = latency
+	p.next = (p.next + 1) % hedgeLatencySamples
+}
+
+// hedgeContextKey is the context key of the HedgePolicy of a request
+type hedgeContextKey struct{}
+
+// withHedgePolicy returns a copy of ctx that carries the HedgePolicy of a request.
+func withHedgePolicy(ctx context.Context, policy HedgePolicy) context.Context {
+	return context.WithValue(ctx, hedgeContextKey{}, policy)
+}
+
+// hedgingTransport is the http.RoundTripper that sends the hedged requests. The policy of the request, if any,
+// takes precedence over the one of the Builder. It is below the middlewares, so every hedge takes a token from
+// the limiter of the Builder itself.
+type hedgingTransport struct {
+	base    http.RoundTripper
+	policy  HedgePolicy
+	limiter RateLimiter
+}
+
+// hedgeResult is the outcome of a request sent by a hedgingTransport
+type hedgeResult struct {
+	// index is the index of the request, in the order they were sent
+	index    int
+	response *http.Response
+	err      error
+	// latency is how long the request took to be answered
+	latency time.Duration
+}
+
+// RoundTrip sends the request, and identical ones each time the delay of the policy elapses without a response,
+// up to the maximum number of requests of the policy. The first response wins and the other requests are canceled.
+// If a request fails while none is in flight, its error is returned.
+func (t *hedgingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy, _ := req.Context().Value(hedgeContextKey{}).(HedgePolicy)
+	if policy == nil {
+		policy = t.policy
+	}
+	if policy == nil || policy.GetMaxRequests() < 2 || !isSafeMethod(req.Method) ||
+		req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.base.RoundTrip(req)
+	}
+	if req.Body != nil {
+		// every request reads its own copy of the body, the original one is closed as a RoundTripper must
+		_ = req.Body.Close()
+	}
+	results := make(chan hedgeResult, policy.GetMaxRequests())
+	var (
+		cancels []context.CancelFunc
+		starts  []time.Time
+		done    []bool
+	)
+	send := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels = append(cancels, cancel)
+		starts = append(starts, time.Now())
+		done = append(done, false)
+		index := len(cancels) - 1
+		attempt := req.Clone(ctx)
+		go func() {
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					results <- hedgeResult{index: index, err: err}
+					return
+				}
+				attempt.Body = body
+			}
+			if index > 0 && t.limiter != nil {
+				// the first request took its token in the middleware
+				if err := t.limiter.Wait(attempt); err != nil {
+					if attempt.Body != nil {
+						_ = attempt.Body.Close()
+					}
+					results <- hedgeResult{index: index, err: err}
+					return
+				}
+			}
+			start := time.Now()
+			response, err := t.base.RoundTrip(attempt)
+			results <- hedgeResult{index: index, response: response, err: err, latency: time.Since(start)}
+		}()
+	}
+	// cancelOthers cancels the requests other than the winner and discards their responses
+	cancelOthers := func(winner, inFlight int) {
+		for i, cancel := range cancels {
+			if i != winner {
+				cancel()
+			}
+		}
+		go discardHedges(results, inFlight)
+	}
+	// observe records the latency of the winner, and the time the requests still in flight have taken so far as
+	// theirs, since they would have taken at least as long. Otherwise the slow requests would never be recorded and
+	// the percentile delay would keep decreasing.
+	observe := func(winner hedgeResult) {
+		policy.Observe(winner.latency)
+		for i := range cancels {
+			if !done[i] {
+				policy.Observe(time.Since(starts[i]))
+			}
+		}
+	}
+	send()
+	inFlight := 1
+	timer := time.NewTimer(policy.Delay())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if len(cancels) < policy.GetMaxRequests() {
+				send()
+				inFlight++
+				timer.Reset(policy.Delay())
+			}
+		case result := <-results:
+			inFlight--
+			done[result.index] = true
+			if result.err != nil && inFlight > 0 {
+				continue
+			}
+			if result.err != nil {
+				cancelOthers(-1, inFlight)
+				return nil, result.err
+			}
+			observe(result)
+			cancelOthers(result.index, inFlight)
+			// the winning request is canceled once its body is closed
+			result.response.Body = &cancelReadCloser{ReadCloser: result.response.Body, cancel: cancels[result.index]}
+			return result.response, nil
+		case <-req.Context().Done():
+			cancelOthers(-1, inFlight)
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// CloseIdleConnections closes the idle connections of the base transport, so that http.Client.CloseIdleConnections
+// still reaches it.
+func (t *hedgingTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// discardHedges closes the responses of the count canceled requests still in flight once they are done.
+func discardHedges(results <-chan hedgeResult, count int) {
+	for ; count > 0; count-- {
+		if result := <-results; result.response != nil {
+			_ = result.response.Body.Close()
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package go_requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newHedgeServer returns a server whose first request is answered after slow and the others right away, and the
+// number of requests it received and saw canceled.
+func newHedgeServer(t *testing.T, slow time.Duration) (server *httptest.Server, requests, canceled *int32) {
+	requests, canceled = new(int32), new(int32)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(requests, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+				atomic.AddInt32(canceled, 1)
+				return
+			case <-time.After(slow):
+			}
+			_, _ = w.Write([]byte("slow"))
+			return
+		}
+		_, _ = w.Write([]byte("fast"))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests, canceled
+}
+
+func Test_hedgingTransport_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		slow         time.Duration
+		method       Method
+		body         string
+		want         string
+		wantRequests int32
+	}{
+		{name: "slow request is hedged", slow: 2 * time.Second, method: http.MethodGet, want: "fast", wantRequests: 2},
+		{name: "fast request is not hedged", slow: 0, method: http.MethodGet, want: "slow", wantRequests: 1},
+		{name: "unsafe method is not hedged", slow: 100 * time.Millisecond, method: http.MethodPost, body: "{}", want: "slow", wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests, _ := newHedgeServer(t, tt.slow)
+			builder := NewBuilder()
+			builder.SetBaseURL(server.URL)
+			builder.SetHedgePolicy(NewHedgePolicy(20 * time.Millisecond))
+			req := NewRequest(tt.method, "/pet")
+			if tt.body != "" {
+				req.SetBody([]byte(tt.body))
+			}
+			resp, err := builder.Build().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.String() != tt.want {
+				t.Errorf("body = %q, want %q", resp.String(), tt.want)
+			}
+			if got := atomic.LoadInt32(requests); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func Test_hedgingTransport_RoundTrip_cancelsLosers(t *testing.T) {
+	server, requests, canceled := newHedgeServer(t, 5*time.Second)
+	builder := NewBuilder()
+	builder.SetBaseURL(server.URL)
+	builder.SetHedgePolicy(NewHedgePolicy(20 * time.Millisecond).SetMaxRequests(3))
+	start := time.Now()
+	resp, err := builder.Build().Get("/pet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.String() != "fast" {
+		t.Errorf("body = %q, want %q", resp.String(), "fast")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request took %v, want the hedged response", elapsed)
+	}
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(canceled) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := atomic.LoadInt32(canceled); got != 1 {
+		t.Errorf("canceled requests = %d, want 1", got)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func Test_hedgingTransport_RoundTrip_requestPolicy(t *testing.T) {
+	server, requests, _ := newHedgeServer(t, 2*time.Second)
+	builder := NewBuilder()
+	builder.SetBaseURL(server.URL)
+	builder.SetHedgePolicy(NewHedgePolicy(20 * time.Millisecond).SetMaxRequests(1))
+	resp, err := builder.Build().Do(NewRequest(http.MethodGet, "/pet").
+		SetHedgePolicy(NewHedgePolicy(20 * time.Millisecond)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.String() != "fast" {
+		t.Errorf("body = %q, want %q", resp.String(), "fast")
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func Test_hedgingTransport_RoundTrip_observesLosers(t *testing.T) {
+	server, _, _ := newHedgeServer(t, 2*time.Second)
+	policy := NewHedgePolicy(20*time.Millisecond).SetPercentile(1, 1)
+	builder := NewBuilder()
+	builder.SetBaseURL(server.URL)
+	builder.SetHedgePolicy(policy)
+	if _, err := builder.Build().Get("/pet"); err != nil {
+		t.Fatal(err)
+	}
+	// the canceled request had been in flight for at least the delay when the hedge won
+	if got := policy.Delay(); got < 20*time.Millisecond {
+		t.Errorf("Delay() = %v, want at least %v", got, 20*time.Millisecond)
+	}
+}
+
+func Test_hedgingTransport_RoundTrip_rateLimiter(t *testing.T) {
+	server, requests, _ := newHedgeServer(t, 300*time.Millisecond)
+	builder := NewBuilder()
+	builder.SetBaseURL(server.URL)
+	builder.SetHedgePolicy(NewHedgePolicy(20 * time.Millisecond))
+	// the first request takes the only token, so the hedge waits for the next one, a second later
+	builder.SetRateLimiter(NewRateLimiter().SetLimit(1, 1))
+	start := time.Now()
+	resp, err := builder.Build().Get("/pet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.String() != "slow" {
+		t.Errorf("body = %q, want %q", resp.String(), "slow")
+	}
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Errorf("request took %v, want the first response", elapsed)
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+// closingTransport is a custom transport that counts the calls to CloseIdleConnections
+type closingTransport struct {
+	http.Transport
+	closed int32
+}
+
+func (t *closingTransport) CloseIdleConnections() {
+	atomic.AddInt32(&t.closed, 1)
+	t.Transport.CloseIdleConnections()
+}
+
+func Test_goHTTPClient_getClient_customTransport(t *testing.T) {
+	server, requests, _ := newHedgeServer(t, 100*time.Millisecond)
+	transport := &closingTransport{}
+	builder := NewBuilder()
+	builder.SetBaseURL(server.URL)
+	builder.SetHTTPClient(&http.Client{Transport: transport})
+	client := builder.Build().(*goHTTPClient)
+	// a request with its own policy is hedged
+	resp, err := client.Do(NewRequest(http.MethodGet, "/pet").SetHedgePolicy(NewHedgePolicy(20 * time.Millisecond)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.String() != "fast" || atomic.LoadInt32(requests) != 2 {
+		t.Errorf("body = %q after %d requests, want %q after 2", resp.String(), atomic.LoadInt32(requests), "fast")
+	}
+	if _, err = client.Get("/pet"); err != nil {
+		t.Fatal(err)
+	}
+	// the other ones go straight to the custom transport
+	if got := client.getClient().Transport; got != http.RoundTripper(transport) {
+		t.Errorf("Transport = %T, want the custom transport", got)
+	}
+	client.getClient().CloseIdleConnections()
+	if got := atomic.LoadInt32(&transport.closed); got != 1 {
+		t.Errorf("CloseIdleConnections() calls = %d, want 1", got)
+	}
+}
+
+func Test_hedgingTransport_CloseIdleConnections(t *testing.T) {
+	transport := &closingTransport{}
+	builder := NewBuilder()
+	builder.SetHTTPClient(&http.Client{Transport: transport})
+	builder.SetHedgePolicy(NewHedgePolicy(20 * time.Millisecond))
+	builder.Build().(*goHTTPClient).getClient().CloseIdleConnections()
+	if got := atomic.LoadInt32(&transport.closed); got != 1 {
+		t.Errorf("CloseIdleConnections() calls = %d, want 1", got)
+	}
+}
+
+func Test_hedgePolicyImpl_Delay(t *testing.T) {
+	tests := []struct {
+		name       string
+		percentile float64
+		minSamples int
+		samples    int
+		want       time.Duration
+	}{
+		{name: "fixed delay", samples: 100, want: 50 * time.Millisecond},
+		{name: "not enough samples", percentile: 0.9, minSamples: 20, samples: 10, want: 50 * time.Millisecond},
+		{name: "percentile", percentile: 0.9, minSamples: 20, samples: 100, want: 90 * time.Millisecond},
+		{name: "maximum", percentile: 1, minSamples: 1, samples: 100, want: 100 * time.Millisecond},
+		{name: "ring buffer", percentile: 0.5, minSamples: 1, samples: 300, want: 172 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewHedgePolicy(50*time.Millisecond).SetPercentile(tt.percentile, tt.minSamples)
+			for i := 1; i <= tt.samples; i++ {
+				policy.Observe(time.Duration(i) * time.Millisecond)
+			}
+			if got := policy.Delay(); got != tt.want {
+				t.Errorf("Delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package go_requests
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Link is a link of the Link header of a response (RFC 8288), e.g. `<https://api.example.com/pets?page=2>; rel="next"`.
+type Link struct {
+	// URL is the target of the link as sent by the server. It may be relative or an RFC 6570 URI template.
+	URL string
+	// Rel is the relation type of the link, e.g. "next". It may hold several space separated relation types.
+	Rel string
+	// Params are the other parameters of the link, e.g. "title" or "type", by lower case name.
+	Params map[string]string
+	// base is the URL of the request that returned the link
+	base *url.URL
+}
+
+// HasRel returns true if rel is one of the relation types of the link. Relation types are case-insensitive.
+func (l Link) HasRel(rel string) bool {
+	for _, r := range strings.Fields(l.Rel) {
+		if strings.EqualFold(r, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expand expands the target of the link as a URI template with the given values, and resolves it against the URL
+// of the request that returned the link. A link that is not a template is only resolved, with nil values.
+//
+//	Example:
+//		link, ok := response.Link("search")
+//		if ok {
+//			url, err := link.Expand(map[string]interface{}{"q": "dogs"})
+//			response, err = client.Get(url)
+//		}
+func (l Link) Expand(values map[string]interface{}) (string, error) {
+	target, err := ExpandURITemplate(l.URL, values)
+	if err != nil {
+		return "", err
+	}
+	if l.base == nil {
+		return target, nil
+	}
+	ref, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	return l.base.ResolveReference(ref).String(), nil
+}
+
+// Links returns the links of the Link headers of the response, in order.
+func (r *Response) Links() []Link {
+	var links []Link
+	for _, value := range r.header.Values("Link") {
+		links = append(links, parseLinks(value, r.url)...)
+	}
+	return links
+}
+
+// Link returns the first link of the response with the relation type rel, e.g. "next".
+func (r *Response) Link(rel string) (Link, bool) {
+	for _, link := range r.Links() {
+		if link.HasRel(rel) {
+			return link, true
+		}
+	}
+	return Link{}, false
+}
+
+// parseLinks parses the value of a Link header, e.g. `<url1>; rel="next", <url2>; rel=prev`.
+// Links whose target is not enclosed in angle brackets are skipped.
+func parseLinks(value string, base *url.URL) []Link {
+	var links []Link
+	for {
+		start := strings.IndexByte(value, '<')
+		if start < 0 {
+			return links
+		}
+		end := strings.IndexByte(value[start:], '>')
+		if end < 0 {
+			return links
+		}
+		link := Link{URL: value[start+1 : start+end], Params: make(map[string]string), base: base}
+		value = value[start+end+1:]
+		// the parameters run until the comma that separates the links, outside of quoted strings
+		for {
+			value = strings.TrimLeft(value, " \t")
+			if value == "" || value[0] == ',' {
+				break
+			}
+			if value[0] != ';' {
+				// not a parameter, skip to the next link
+				value = value[strings.IndexAny(value+",", ","):]
+				continue
+			}
+			var name, paramValue string
+			name, paramValue, value = parseLinkParam(value[1:])
+			if name == "" {
+				continue
+			}
+			if name == "rel" {
+				if link.Rel == "" {
+					link.Rel = paramValue
+				}
+				continue
+			}
+			if _, ok := link.Params[name]; !ok {
+				link.Params[name] = paramValue
+			}
+		}
+		links = append(links, link)
+	}
+}
+
+// parseLinkParam parses a parameter of a link, e.g. ` rel="next"`, and returns its lower case name, its unquoted
+// value and the rest of the header value.
+func parseLinkParam(s string) (name, value, rest string) {
+	s = strings.TrimLeft(s, " \t")
+	i := strings.IndexAny(s, "=;,")
+	if i < 0 {
+		return strings.ToLower(strings.TrimSpace(s)), "", ""
+	}
+	name = strings.ToLower(strings.TrimSpace(s[:i]))
+	if s[i] != '=' {
+		return name, "", s[i:]
+	}
+	s = strings.TrimLeft(s[i+1:], " \t")
+	if strings.HasPrefix(s, `"`) {
+		var b strings.Builder
+		for j := 1; j < len(s); j++ {
+			switch s[j] {
+			case '\\':
+				if j+1 < len(s) {
+					j++
+					b.WriteByte(s[j])
+				}
+			case '"':
+				return name, b.String(), s[j+1:]
+			default:
+				b.WriteByte(s[j])
+			}
+		}
+		return name, b.String(), ""
+	}
+	i = strings.IndexAny(s, ";,")
+	if i < 0 {
+		return name, strings.TrimSpace(s), ""
+	}
+	return name, strings.TrimSpace(s[:i]), s[i:]
+}
@@ -0,0 +1,80 @@
+package go_requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func Test_parseLinks(t *testing.T) {
+	base, _ := url.Parse("https://api.example.com/v2/pets?page=1")
+	tests := []struct {
+		name  string
+		value string
+		want  []Link
+	}{
+		{
+			name:  "several",
+			value: `<https://api.example.com/v2/pets?page=2>; rel="next", </v2/pets?page=9>; rel=last`,
+			want: []Link{
+				{URL: "https://api.example.com/v2/pets?page=2", Rel: "next", Params: map[string]string{}, base: base},
+				{URL: "/v2/pets?page=9", Rel: "last", Params: map[string]string{}, base: base},
+			},
+		},
+		{
+			name:  "params",
+			value: `<search{?q}>; REL="search alternate"; title="a \"quoted\", title"; type=text/html`,
+			want: []Link{
+				{URL: "search{?q}", Rel: "search alternate", Params: map[string]string{"title": `a "quoted", title`, "type": "text/html"}, base: base},
+			},
+		},
+		{
+			name:  "malformed",
+			value: `https://example.com; rel=next, <ok>; rel=prev`,
+			want:  []Link{{URL: "ok", Rel: "prev", Params: map[string]string{}, base: base}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLinks(tt.value, base); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLinks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponse_Link(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `<pets?page=2>; rel="next"`)
+		w.Header().Add("Link", `</v2/search{?q,limit}>; rel="search"`)
+	}))
+	defer server.Close()
+	client := NewBuilder().Build()
+	response, err := client.Get(server.URL + "/v2/pets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(response.Links()); got != 2 {
+		t.Fatalf("len(Links()) = %d, want 2", got)
+	}
+	next, ok := response.Link("next")
+	if !ok {
+		t.Fatal("Link(next) not found")
+	}
+	if got, err := next.Expand(nil); err != nil || got != server.URL+"/v2/pets?page=2" {
+		t.Errorf("Expand() = %v, %v, want %v", got, err, server.URL+"/v2/pets?page=2")
+	}
+	search, ok := response.Link("SEARCH")
+	if !ok {
+		t.Fatal("Link(search) not found")
+	}
+	got, err := search.Expand(map[string]interface{}{"q": "good dogs"})
+	if want := server.URL + "/v2/search?q=good%20dogs"; err != nil || got != want {
+		t.Errorf("Expand() = %v, %v, want %v", got, err, want)
+	}
+	if _, ok := response.Link("prev"); ok {
+		t.Error("Link(prev) found, want none")
+	}
+}
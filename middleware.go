@@ -0,0 +1,36 @@
+package go_requests
+
+import "net/http"
+
+// RoundTripFunc sends a single HTTP request and returns its Response.
+type RoundTripFunc func(req *http.Request) (*Response, error)
+
+// Middleware wraps a RoundTripFunc with cross-cutting behaviour such as logging, metrics or authentication.
+// A Middleware may inspect or mutate the outgoing request before calling next, inspect or replace the Response
+// returned by next, or short-circuit the chain by returning a Response (see NewResponse) or an error without calling next.
+//
+// Middlewares are registered with Builder.Use. The first registered middleware is the outermost one:
+// it sees the request first and the response last. The chain runs once per attempt, so when a RetryPolicy
+// is configured every retry passes through all middlewares again.
+//
+//	Example:
+//		builder.Use(func(next go_requests.RoundTripFunc) go_requests.RoundTripFunc {
+//			return func(req *http.Request) (*go_requests.Response, error) {
+//				start := time.Now()
+//				res, err := next(req)
+//				log.Printf("%s %s took %s", req.Method, req.URL, time.Since(start))
+//				return res, err
+//			}
+//		})
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// chain wraps the given RoundTripFunc with the middlewares so that the first middleware is the outermost one.
+func chain(final RoundTripFunc, middlewares ...Middleware) RoundTripFunc {
+	next := final
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] != nil {
+			next = middlewares[i](next)
+		}
+	}
+	return next
+}
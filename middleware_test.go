@@ -0,0 +1,90 @@
+package go_requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_builderImpl_Use(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(r.Header.Get("X-Trace")))
+	}))
+	defer server.Close()
+
+	var order []string
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*Response, error) {
+				order = append(order, name+" request")
+				req.Header.Add("X-Trace", name)
+				res, err := next(req)
+				order = append(order, name+" response")
+				return res, err
+			}
+		}
+	}
+
+	tests := []struct {
+		name        string
+		middlewares []Middleware
+		wantBody    string
+		wantStatus  int
+		wantOrder   []string
+		wantHits    int32
+	}{
+		{
+			name:        "ordering",
+			middlewares: []Middleware{record("first"), record("second")},
+			wantBody:    "first",
+			wantStatus:  http.StatusOK,
+			wantOrder:   []string{"first request", "second request", "second response", "first response"},
+			wantHits:    1,
+		},
+		{
+			name: "short circuit",
+			middlewares: []Middleware{
+				record("first"),
+				func(next RoundTripFunc) RoundTripFunc {
+					return func(req *http.Request) (*Response, error) {
+						return NewResponse(http.StatusTeapot, nil, []byte("cached")), nil
+					}
+				},
+				record("never"),
+			},
+			wantBody:   "cached",
+			wantStatus: http.StatusTeapot,
+			wantOrder:  []string{"first request", "first response"},
+			wantHits:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order = nil
+			atomic.StoreInt32(&hits, 0)
+			builder := NewBuilder()
+			builder.Use(tt.middlewares...)
+			res, err := builder.Build().Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if res.String() != tt.wantBody {
+				t.Errorf("Get() body = %v, want %v", res.String(), tt.wantBody)
+			}
+			if res.StatusCode() != tt.wantStatus {
+				t.Errorf("Get() status = %v, want %v", res.StatusCode(), tt.wantStatus)
+			}
+			if !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("middleware order = %v, want %v", order, tt.wantOrder)
+			}
+			if got := atomic.LoadInt32(&hits); got != tt.wantHits {
+				t.Errorf("server hits = %v, want %v", got, tt.wantHits)
+			}
+		})
+	}
+}
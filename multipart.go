@@ -0,0 +1,257 @@
+package go_requests
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultPartContentType is the content type of the file parts whose type cannot be guessed from their name
+const defaultPartContentType = "application/octet-stream"
+
+// Multipart is the interface that builds multipart/form-data request bodies.
+// The parts are written while the request is sent, so files are never loaded in memory.
+// It is sent with Client.PostMultipart or Client.PutMultipart, which also set the Content-Type header with its boundary.
+//
+//	Example:
+//		body := go_requests.NewMultipart().
+//			AddField("name", "doggie").
+//			AddFile("photo", "/tmp/doggie.png")
+//		response, err := client.PostMultipart(ctx, "https://petstore.swagger.io/v2/pet/1/uploadImage", body)
+type Multipart interface {
+	// AddField adds a form field.
+	AddField(name, value string) Multipart
+	// AddFile adds the file at path, which is opened when the body is sent.
+	// The content type is guessed from the extension of the file unless it is set in headers.
+	AddFile(fieldName, path string, headers ...http.Header) Multipart
+	// AddReader adds a file part whose content is read from reader.
+	// The content type is guessed from the extension of fileName unless it is set in headers.
+	AddReader(fieldName, fileName string, reader io.Reader, headers ...http.Header) Multipart
+	// AddPart adds a part with arbitrary headers, e.g. a JSON document with its own Content-Type.
+	AddPart(headers http.Header, reader io.Reader) Multipart
+	// SetBoundary sets the boundary that separates the parts. By default, a random boundary is used.
+	SetBoundary(boundary string) Multipart
+	// ContentType returns the value of the Content-Type header of the body.
+	ContentType() string
+	// Reader returns a new reader of the body. Every call writes the parts again, which fails for the parts
+	// added with a reader that is not an io.Seeker once it has been consumed.
+	Reader() io.ReadCloser
+}
+
+// multipartPart is a part of a multipartImpl
+type multipartPart struct {
+	header textproto.MIMEHeader
+	// path is the file read by the part, if any
+	path string
+	// reader is the content of the part when path is empty
+	reader io.Reader
+	// offset is the position of reader at which the part starts, or -1 if reader is not an io.Seeker
+	offset int64
+}
+
+// multipartImpl is the implementation of the Multipart interface
+type multipartImpl struct {
+	boundary string
+	parts    []*multipartPart
+	err      error
+}
+
+// NewMultipart returns a new empty Multipart body.
+func NewMultipart() Multipart {
+	return &multipartImpl{
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+	}
+}
+
+// AddField adds a form field.
+func (m *multipartImpl) AddField(name, value string) Multipart {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", formDataDisposition(name, ""))
+	m.parts = append(m.parts, &multipartPart{header: header, reader: strings.NewReader(value), offset: 0})
+	return m
+}
+
+// AddFile adds the file at path.
+func (m *multipartImpl) AddFile(fieldName, path string, headers ...http.Header) Multipart {
+	m.parts = append(m.parts, &multipartPart{
+		header: fileHeader(fieldName, filepath.Base(path), headers...),
+		path:   path,
+		offset: -1,
+	})
+	return m
+}
+
+// AddReader adds a file part read from reader.
+func (m *multipartImpl) AddReader(fieldName, fileName string, reader io.Reader, headers ...http.Header) Multipart {
+	m.parts = append(m.parts, &multipartPart{
+		header: fileHeader(fieldName, fileName, headers...),
+		reader: reader,
+		offset: readerOffset(reader),
+	})
+	return m
+}
+
+// AddPart adds a part with arbitrary headers.
+func (m *multipartImpl) AddPart(headers http.Header, reader io.Reader) Multipart {
+	m.parts = append(m.parts, &multipartPart{
+		header: textproto.MIMEHeader(headers.Clone()),
+		reader: reader,
+		offset: readerOffset(reader),
+	})
+	return m
+}
+
+// SetBoundary sets the boundary that separates the parts.
+// An invalid boundary is reported by the reader of the body.
+func (m *multipartImpl) SetBoundary(boundary string) Multipart {
+	if err := multipart.NewWriter(io.Discard).SetBoundary(boundary); err != nil {
+		m.err = fmt.Errorf("multipart: %w", err)
+		return m
+	}
+	m.boundary = boundary
+	return m
+}
+
+// ContentType returns the multipart/form-data content type with the boundary of the body.
+func (m *multipartImpl) ContentType() string {
+	return mime.FormatMediaType("multipart/form-data", map[string]string{"boundary": m.boundary})
+}
+
+// Reader returns a new reader of the body. The parts are written by a goroutine that stops when the reader is closed.
+func (m *multipartImpl) Reader() io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(m.writeTo(writer))
+	}()
+	return reader
+}
+
+// replayable returns true if every part can be written more than once.
+func (m *multipartImpl) replayable() bool {
+	for _, part := range m.parts {
+		if part.path == "" && part.offset < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// writeTo writes the whole body to w.
+func (m *multipartImpl) writeTo(w io.Writer) error {
+	if m.err != nil {
+		return m.err
+	}
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(m.boundary); err != nil {
+		return err
+	}
+	for _, part := range m.parts {
+		if err := part.writeTo(writer); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// writeTo writes the part to the multipart writer.
+func (p *multipartPart) writeTo(writer *multipart.Writer) error {
+	w, err := writer.CreatePart(p.header)
+	if err != nil {
+		return err
+	}
+	if p.path != "" {
+		file, err := os.Open(p.path)
+		if err != nil {
+			return err
+		}
+		defer func(file *os.File) {
+			_ = file.Close()
+		}(file)
+		_, err = io.Copy(w, file)
+		return err
+	}
+	if p.reader == nil {
+		return nil
+	}
+	if p.offset >= 0 {
+		if _, err = p.reader.(io.Seeker).Seek(p.offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	_, err = io.Copy(w, p.reader)
+	return err
+}
+
+// multipartBody is the request body of a Multipart.
+// It is recognized by newRequest, which replays it by writing the parts again.
+type multipartBody struct {
+	io.ReadCloser
+	multipart *multipartImpl
+}
+
+// newMultipartBody returns the request body of m.
+func newMultipartBody(m Multipart) (*multipartBody, error) {
+	impl, ok := m.(*multipartImpl)
+	if !ok {
+		return nil, errors.New("multipart: unsupported Multipart implementation")
+	}
+	return &multipartBody{ReadCloser: impl.Reader(), multipart: impl}, nil
+}
+
+// getBody returns the GetBody function of the request, or nil if the body cannot be sent again.
+func (b *multipartBody) getBody() func() (io.ReadCloser, error) {
+	if !b.multipart.replayable() {
+		return nil
+	}
+	return func() (io.ReadCloser, error) {
+		return b.multipart.Reader(), nil
+	}
+}
+
+// fileHeader returns the header of a file part, with its content type guessed from fileName unless set in headers.
+func fileHeader(fieldName, fileName string, headers ...http.Header) textproto.MIMEHeader {
+	header := make(textproto.MIMEHeader)
+	for key, values := range getHeader(headers...) {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	header.Set("Content-Disposition", formDataDisposition(fieldName, fileName))
+	if header.Get("Content-Type") == "" {
+		contentType := mime.TypeByExtension(filepath.Ext(fileName))
+		if contentType == "" {
+			contentType = defaultPartContentType
+		}
+		header.Set("Content-Type", contentType)
+	}
+	return header
+}
+
+// formDataDisposition returns the Content-Disposition of a form-data part.
+func formDataDisposition(name, fileName string) string {
+	params := map[string]string{"name": name}
+	if fileName != "" {
+		params["filename"] = fileName
+	}
+	return mime.FormatMediaType("form-data", params)
+}
+
+// readerOffset returns the current offset of reader, or -1 if it is not an io.Seeker.
+func readerOffset(reader io.Reader) int64 {
+	seeker, ok := reader.(io.Seeker)
+	if !ok {
+		return -1
+	}
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	return offset
+}
@@ -0,0 +1,120 @@
+package go_requests
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_goHTTPClient_PostMultipart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doggie.png")
+	if err := os.WriteFile(path, []byte("png data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var attempts int
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != "multipart/form-data" || params["boundary"] != "test-boundary" {
+			t.Errorf("Content-Type = %v", r.Header.Get("Content-Type"))
+		}
+		reader, err := r.MultipartReader()
+		if err != nil {
+			t.Fatalf("MultipartReader() error = %v", err)
+		}
+		got = nil
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			content, _ := io.ReadAll(part)
+			got = append(got, part.FormName()+"|"+part.FileName()+"|"+part.Header.Get("Content-Type")+"|"+string(content))
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		body         Multipart
+		wantAttempts int
+		want         []string
+	}{
+		{
+			name: "replayable parts",
+			body: NewMultipart().
+				SetBoundary("test-boundary").
+				AddField("name", "doggie").
+				AddFile("photo", path).
+				AddReader("notes", "notes.txt", strings.NewReader("good boy")).
+				AddPart(http.Header{
+					"Content-Disposition": {`form-data; name="metadata"`},
+					"Content-Type":        {"application/json"},
+				}, strings.NewReader(`{"id":1}`)),
+			wantAttempts: 2,
+			want: []string{
+				"name|||doggie",
+				"photo|doggie.png|image/png|png data",
+				"notes|notes.txt|text/plain; charset=utf-8|good boy",
+				"metadata||application/json|{\"id\":1}",
+			},
+		},
+		{
+			name: "reader that cannot be replayed",
+			body: NewMultipart().
+				SetBoundary("test-boundary").
+				AddReader("data", "data.bin", io.MultiReader(strings.NewReader("raw")), http.Header{
+					"Content-Type": {"application/x-custom"},
+				}),
+			wantAttempts: 1,
+			want: []string{
+				"data|data.bin|application/x-custom|raw",
+			},
+		},
+	}
+	builder := NewBuilder()
+	builder.SetRetryPolicy(NewRetryPolicy().
+		SetBackoff(time.Millisecond, time.Millisecond).
+		SetRetryNonIdempotent(true))
+	client := builder.Build()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts = 0
+			res, err := client.PostMultipart(context.Background(), server.URL, tt.body)
+			if err != nil {
+				t.Fatalf("PostMultipart() error = %v", err)
+			}
+			if res.Attempts() != tt.wantAttempts {
+				t.Errorf("PostMultipart() attempts = %v, want %v", res.Attempts(), tt.wantAttempts)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostMultipart() parts = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_multipartImpl_Reader(t *testing.T) {
+	body := NewMultipart().SetBoundary("invalid boundary ").AddField("name", "value")
+	if _, err := io.ReadAll(body.Reader()); err == nil {
+		t.Errorf("Reader() expected an error for an invalid boundary")
+	}
+	body = NewMultipart().AddFile("file", filepath.Join(t.TempDir(), "missing.txt"))
+	if _, err := io.ReadAll(body.Reader()); err == nil {
+		t.Errorf("Reader() expected an error for a missing file")
+	}
+}
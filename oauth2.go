@@ -0,0 +1,271 @@
+package go_requests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultTokenExpiryDelta is how long before its expiry a cached token is considered expired and refreshed
+const defaultTokenExpiryDelta = 10 * time.Second
+
+// Token is an OAuth2 token as returned by a token endpoint.
+type Token struct {
+	// AccessToken is the token that authorizes the requests.
+	AccessToken string
+	// TokenType is the type of the token, usually Bearer.
+	TokenType string
+	// RefreshToken is used to obtain a new access token when the current one expires. It may be empty.
+	RefreshToken string
+	// Expiry is the time the access token expires at. A zero Expiry means the token never expires.
+	Expiry time.Time
+}
+
+// Valid returns true if the token is set and does not expire in the next few seconds.
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(defaultTokenExpiryDelta).Before(t.Expiry)
+}
+
+// String returns the value of the Authorization header for the token.
+func (t *Token) String() string {
+	tokenType := t.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, string(AuthorizationTypeBearer)) {
+		tokenType = string(AuthorizationTypeBearer)
+	}
+	return tokenType + " " + t.AccessToken
+}
+
+// TokenSource is the interface that provides OAuth2 tokens.
+// It is attached to a client with Builder.SetTokenSource and must be safe for concurrent use.
+type TokenSource interface {
+	// Token returns a valid token, fetching a new one when needed.
+	Token(ctx context.Context) (*Token, error)
+}
+
+// OAuth2Config is the configuration of an OAuth2 token endpoint.
+type OAuth2Config struct {
+	// TokenURL is the URL of the token endpoint.
+	TokenURL string
+	// ClientID is the identifier of the application.
+	ClientID string
+	// ClientSecret is the secret of the application.
+	ClientSecret string
+	// Scopes are the optional scopes requested.
+	Scopes []string
+	// AuthInParams sends the client credentials in the request body instead of the Authorization header.
+	AuthInParams bool
+	// HTTPClient is the client used to call the token endpoint. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// NewClientCredentialsTokenSource returns a TokenSource that uses the client credentials grant (RFC 6749 section 4.4).
+//
+//	Example:
+//		builder.SetTokenSource(go_requests.NewClientCredentialsTokenSource(go_requests.OAuth2Config{
+//			TokenURL:     "https://auth.example.com/oauth/token",
+//			ClientID:     "id",
+//			ClientSecret: "secret",
+//		}))
+func NewClientCredentialsTokenSource(config OAuth2Config) TokenSource {
+	return &cachingTokenSource{
+		config: config,
+		grant: func() url.Values {
+			return url.Values{"grant_type": {"client_credentials"}}
+		},
+	}
+}
+
+// NewRefreshTokenSource returns a TokenSource that uses the refresh token grant (RFC 6749 section 6).
+// If the token endpoint rotates the refresh token, the new one is used for the next refresh.
+func NewRefreshTokenSource(config OAuth2Config, refreshToken string) TokenSource {
+	return &cachingTokenSource{
+		config: config,
+		token:  &Token{RefreshToken: refreshToken},
+	}
+}
+
+// NewPasswordTokenSource returns a TokenSource that uses the resource owner password credentials grant (RFC 6749 section 4.3).
+// When the token endpoint issues a refresh token, it is used instead of the password to get the next token.
+func NewPasswordTokenSource(config OAuth2Config, username, password string) TokenSource {
+	return &cachingTokenSource{
+		config: config,
+		grant: func() url.Values {
+			return url.Values{
+				"grant_type": {"password"},
+				"username":   {username},
+				"password":   {password},
+			}
+		},
+	}
+}
+
+// cachingTokenSource is the implementation of the TokenSource interface.
+// It caches the token until shortly before it expires and makes sure only one refresh is in flight at a time.
+type cachingTokenSource struct {
+	mu     sync.Mutex
+	config OAuth2Config
+	// grant returns the parameters of the grant used when there is no refresh token
+	grant func() url.Values
+	token *Token
+}
+
+// Token returns the cached token if it is still valid, otherwise it fetches a new one.
+func (s *cachingTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	var err error
+	if s.token != nil && s.token.RefreshToken != "" {
+		var token *Token
+		token, err = s.fetch(ctx, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {s.token.RefreshToken},
+		})
+		if err == nil {
+			if token.RefreshToken == "" {
+				token.RefreshToken = s.token.RefreshToken
+			}
+			s.token = token
+			return token, nil
+		}
+	}
+	if s.grant == nil {
+		if err == nil {
+			err = errors.New("oauth2: no refresh token available")
+		}
+		return nil, err
+	}
+	token, err := s.fetch(ctx, s.grant())
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// invalidate drops the cached token if it is still the given one, so that the next call of Token fetches a new one.
+// The refresh token is kept.
+func (s *cachingTokenSource) invalidate(token *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = &Token{RefreshToken: token.RefreshToken}
+	}
+}
+
+// fetch calls the token endpoint with the given grant parameters.
+func (s *cachingTokenSource) fetch(ctx context.Context, params url.Values) (*Token, error) {
+	if len(s.config.Scopes) > 0 {
+		params.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+	if s.config.AuthInParams {
+		params.Set("client_id", s.config.ClientID)
+		if s.config.ClientSecret != "" {
+			params.Set("client_secret", s.config.ClientSecret)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", string(jsonContentType))
+	if !s.config.AuthInParams {
+		req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+	}
+	client := s.config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		AccessToken      string      `json:"access_token"`
+		TokenType        string      `json:"token_type"`
+		RefreshToken     string      `json:"refresh_token"`
+		ExpiresIn        json.Number `json:"expires_in"`
+		Error            string      `json:"error"`
+		ErrorDescription string      `json:"error_description"`
+	}
+	if err = json.Unmarshal(body, &result); err != nil && response.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("oauth2: cannot parse token response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK || result.Error != "" {
+		return nil, fmt.Errorf("oauth2: token request failed with status %q: %s %s",
+			response.Status, result.Error, result.ErrorDescription)
+	}
+	if result.AccessToken == "" {
+		return nil, errors.New("oauth2: server response is missing access_token")
+	}
+	token := &Token{
+		AccessToken:  result.AccessToken,
+		TokenType:    result.TokenType,
+		RefreshToken: result.RefreshToken,
+	}
+	if seconds, err := result.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		token.Expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	return token, nil
+}
+
+// oauth2Middleware sets the token of the source as the Authorization header of a clone of the request,
+// unless the caller set the Authorization header. The token is asked from the source for every attempt, so that a
+// token that expires during the backoff of a retry is not sent again. When the server answers 401 Unauthorized,
+// the token is refreshed and the request is sent once more.
+func oauth2Middleware(source TokenSource) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			if hasExplicitAuthorization(req.Context()) {
+				return next(req)
+			}
+			token, err := source.Token(req.Context())
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set("Authorization", token.String())
+			response, err := next(req)
+			if err != nil || response.StatusCode() != http.StatusUnauthorized {
+				return response, err
+			}
+			invalidator, ok := source.(interface{ invalidate(token *Token) })
+			if !ok || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+				return response, err
+			}
+			invalidator.invalidate(token)
+			if token, err = source.Token(req.Context()); err != nil {
+				return response, nil
+			}
+			retry := req.Clone(req.Context())
+			if req.GetBody != nil {
+				if retry.Body, err = req.GetBody(); err != nil {
+					return response, nil
+				}
+			}
+			_ = response.Close()
+			retry.Header.Set("Authorization", token.String())
+			return next(retry)
+		}
+	}
+}
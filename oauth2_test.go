@@ -0,0 +1,300 @@
+package go_requests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTokenServer returns a token endpoint that issues numbered tokens and records the grant types it received.
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var grants []string
+	var issued int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("token endpoint cannot parse form: %v", err)
+		}
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "secret" {
+			if r.PostForm.Get("client_id") != "client" || r.PostForm.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+				return
+			}
+		}
+		grant := r.PostForm.Get("grant_type")
+		switch grant {
+		case "password":
+			if r.PostForm.Get("username") != "user" || r.PostForm.Get("password") != "pass" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+		case "refresh_token":
+			grant += ":" + r.PostForm.Get("refresh_token")
+		}
+		mu.Lock()
+		grants = append(grants, grant)
+		mu.Unlock()
+		n := atomic.AddInt32(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d,"refresh_token":"refresh-%d"}`,
+			n, expiresIn, n)
+	}))
+	return server, &grants
+}
+
+// newProtectedServer returns an API that answers with the Authorization header it received,
+// rejecting the tokens listed in revoked.
+func newProtectedServer(revoked ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		auth := r.Header.Get("Authorization")
+		for _, token := range revoked {
+			if auth == "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(auth + " " + string(body)))
+	}))
+}
+
+func Test_cachingTokenSource_Token(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     func(config OAuth2Config) TokenSource
+		authParams bool
+		expiresIn  int
+		calls      int
+		wantToken  string
+		wantGrants []string
+	}{
+		{
+			name:       "client credentials cached",
+			source:     NewClientCredentialsTokenSource,
+			expiresIn:  3600,
+			calls:      3,
+			wantToken:  "token-1",
+			wantGrants: []string{"client_credentials"},
+		},
+		{
+			name:       "client credentials in params",
+			source:     NewClientCredentialsTokenSource,
+			authParams: true,
+			expiresIn:  3600,
+			calls:      1,
+			wantToken:  "token-1",
+			wantGrants: []string{"client_credentials"},
+		},
+		{
+			name:       "expired token refreshed",
+			source:     NewClientCredentialsTokenSource,
+			expiresIn:  1,
+			calls:      2,
+			wantToken:  "token-2",
+			wantGrants: []string{"client_credentials", "refresh_token:refresh-1"},
+		},
+		{
+			name: "refresh token",
+			source: func(config OAuth2Config) TokenSource {
+				return NewRefreshTokenSource(config, "initial")
+			},
+			expiresIn:  1,
+			calls:      2,
+			wantToken:  "token-2",
+			wantGrants: []string{"refresh_token:initial", "refresh_token:refresh-1"},
+		},
+		{
+			name: "password",
+			source: func(config OAuth2Config) TokenSource {
+				return NewPasswordTokenSource(config, "user", "pass")
+			},
+			expiresIn:  3600,
+			calls:      2,
+			wantToken:  "token-1",
+			wantGrants: []string{"password"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, grants := newTokenServer(t, tt.expiresIn)
+			defer server.Close()
+			source := tt.source(OAuth2Config{
+				TokenURL:     server.URL,
+				ClientID:     "client",
+				ClientSecret: "secret",
+				AuthInParams: tt.authParams,
+			})
+			var token *Token
+			var err error
+			for i := 0; i < tt.calls; i++ {
+				if token, err = source.Token(context.Background()); err != nil {
+					t.Fatalf("Token() error = %v", err)
+				}
+			}
+			if token.AccessToken != tt.wantToken {
+				t.Errorf("Token() = %v, want %v", token.AccessToken, tt.wantToken)
+			}
+			if fmt.Sprint(*grants) != fmt.Sprint(tt.wantGrants) {
+				t.Errorf("grants = %v, want %v", *grants, tt.wantGrants)
+			}
+		})
+	}
+}
+
+func Test_cachingTokenSource_TokenError(t *testing.T) {
+	server, _ := newTokenServer(t, 3600)
+	defer server.Close()
+	source := NewPasswordTokenSource(OAuth2Config{
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+	}, "user", "wrong")
+	if _, err := source.Token(context.Background()); err == nil {
+		t.Errorf("Token() expected an error for invalid credentials")
+	}
+}
+
+func Test_goHTTPClient_TokenSource(t *testing.T) {
+	tokenServer, grants := newTokenServer(t, 3600)
+	defer tokenServer.Close()
+	api := newProtectedServer("token-1")
+	defer api.Close()
+
+	builder := NewBuilder()
+	builder.SetAuthorization(NewAuthorization().Basic("ignored", "ignored"))
+	builder.SetTokenSource(NewClientCredentialsTokenSource(OAuth2Config{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+	}))
+	client := builder.Build()
+
+	// token-1 is rejected, so the request is replayed with token-2
+	res, err := client.Put(api.URL, []byte("payload"))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if want := "Bearer token-2 payload"; res.String() != want {
+		t.Errorf("Put() = %q, want %q", res.String(), want)
+	}
+
+	res, err = client.GetWithContext(context.Background(), api.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if want := "Bearer token-2 "; res.String() != want {
+		t.Errorf("Get() = %q, want %q", res.String(), want)
+	}
+	if len(*grants) != 2 {
+		t.Errorf("token requests = %v, want 2", *grants)
+	}
+}
+
+func Test_goHTTPClient_TokenSource_retry(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{name: "fresh token for every attempt", want: []string{"Bearer token-1", "Bearer token-2"}},
+		{name: "header of the caller", header: "Bearer mine", want: []string{"Bearer mine", "Bearer mine"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the tokens expire within the expiry margin, so every attempt needs a new one
+			tokenServer, _ := newTokenServer(t, 1)
+			defer tokenServer.Close()
+			var got []string
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = append(got, r.Header.Get("Authorization"))
+				if len(got) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer api.Close()
+
+			builder := NewBuilder()
+			builder.SetRetryPolicy(NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond))
+			builder.SetTokenSource(NewClientCredentialsTokenSource(OAuth2Config{
+				TokenURL:     tokenServer.URL,
+				ClientID:     "client",
+				ClientSecret: "secret",
+			}))
+			req := NewRequest(http.MethodGet, api.URL)
+			if tt.header != "" {
+				req.SetHeader("Authorization", tt.header)
+			}
+			res, err := builder.Build().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Attempts() != 2 {
+				t.Fatalf("attempts = %d, want 2", res.Attempts())
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cachingTokenSource_TokenConcurrent(t *testing.T) {
+	server, grants := newTokenServer(t, 3600)
+	defer server.Close()
+	source := NewClientCredentialsTokenSource(OAuth2Config{
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := source.Token(context.Background())
+			if err != nil {
+				t.Errorf("Token() error = %v", err)
+				return
+			}
+			if token.AccessToken != "token-1" {
+				t.Errorf("Token() = %v, want %v", token.AccessToken, "token-1")
+			}
+		}()
+	}
+	wg.Wait()
+	if len(*grants) != 1 {
+		t.Errorf("token requests = %v, want 1", *grants)
+	}
+}
+
+func TestToken_Valid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token *Token
+		want  bool
+	}{
+		{name: "nil", token: nil, want: false},
+		{name: "empty", token: &Token{}, want: false},
+		{name: "no expiry", token: &Token{AccessToken: "a"}, want: true},
+		{name: "expired", token: &Token{AccessToken: "a", Expiry: time.Now().Add(-time.Second)}, want: false},
+		{name: "about to expire", token: &Token{AccessToken: "a", Expiry: time.Now().Add(time.Second)}, want: false},
+		{name: "valid", token: &Token{AccessToken: "a", Expiry: time.Now().Add(time.Hour)}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.token.Valid(); got != tt.want {
+				t.Errorf("Valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,26 @@
 package go_requests
 
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ArrayStyle is the way the keys with several values are encoded in the query of the URL.
+type ArrayStyle int
+
+const (
+	// ArrayStyleRepeat repeats the key for every value, e.g. id=1&id=2. It is the default style.
+	ArrayStyleRepeat ArrayStyle = iota
+	// ArrayStyleComma joins the values with commas, e.g. id=1,2
+	ArrayStyleComma
+	// ArrayStyleBrackets appends [] to the key for every value, e.g. id[]=1&id[]=2
+	ArrayStyleBrackets
+	// ArrayStyleIndexed appends the index of every value to the key, e.g. id[0]=1&id[1]=2
+	ArrayStyleIndexed
+)
+
 // QueryParams is the interface for query params. It is used to add, set, get, delete and clone query params.
 type QueryParams interface {
 	// Add adds the key, value pair to the query params.
@@ -15,86 +36,164 @@ type QueryParams interface {
 	// Del deletes the values associated with key.
 	Del(key string) QueryParams
 	// Values returns the values map.
-	Values() map[string]string
+	Values() map[string][]string
 	// Clone returns a copy of the QueryParams.
 	Clone() QueryParams
 	// Reset resets the QueryParams to the initial state.
 	Reset() QueryParams
 	// Len returns the number of query params.
 	Len() int
+	// SetArrayStyle sets how the keys with several values are encoded. Keys with a single value are always
+	// encoded as key=value.
+	SetArrayStyle(style ArrayStyle) QueryParams
+	// ArrayStyle returns how the keys with several values are encoded.
+	ArrayStyle() ArrayStyle
+	// Encode encodes the query params in URL encoded form, sorted by key, with the configured ArrayStyle.
+	Encode() string
+	// SetStruct sets the fields of the struct v that have a query tag, e.g. `query:"status,omitempty"`,
+	// replacing the values of their keys. Slices are added as several values.
+	SetStruct(v interface{}) error
 }
 
 // implementation of QueryParams
 type queryParams struct {
-	values map[string]string
-	// contains filtered or unexported fields=
+	values map[string][]string
+	style  ArrayStyle
 }
 
 // Add adds the key, value pair to the query params.
-func (q queryParams) Add(key, value string) QueryParams {
-	// add the key, value pair to the query params.
-	// If the key already exists, the value will be appended to the existing values. if not, create a new one
-	q.values[key] = value
+func (q *queryParams) Add(key, value string) QueryParams {
+	q.values[key] = append(q.values[key], value)
 	return q
 }
 
 // Set sets the key, value pair to the query params.
-func (q queryParams) Set(key, value string) QueryParams {
-	// Set sets the key, value pair to the query params.
-	// If the key already exists, the value will be replaced with the new value.
-	q.values[key] = value
+func (q *queryParams) Set(key, value string) QueryParams {
+	q.values[key] = []string{value}
 	return q
 }
 
 // Get gets the first value associated with the given key.
-func (q queryParams) Get(key string) string {
-	// Get gets the first value associated with the given key.
-	// If there are no values associated with the key, Get returns "".
-	// To access multiple values, use the map directly.
+func (q *queryParams) Get(key string) string {
 	if len(q.values[key]) > 0 {
-		return q.values[key]
+		return q.values[key][0]
 	}
 	return ""
 }
 
 // Del deletes the values associated with key.
-func (q queryParams) Del(key string) QueryParams {
-	// Del deletes the values associated with key.
+func (q *queryParams) Del(key string) QueryParams {
 	delete(q.values, key)
 	return q
 }
 
 // Values returns the values map.
-func (q queryParams) Values() map[string]string {
+func (q *queryParams) Values() map[string][]string {
 	return q.values
 }
 
 // Clone returns a copy of the QueryParams.
-func (q queryParams) Clone() QueryParams {
-	//clone the queryParams
-	clone := NewQueryParams()
+func (q *queryParams) Clone() QueryParams {
+	clone := NewQueryParams().SetArrayStyle(q.style)
 	for key, values := range q.values {
 		for _, value := range values {
-			clone.Add(key, string(value))
+			clone.Add(key, value)
 		}
 	}
 	return clone
 }
 
-// Reset resets the QueryParams to the initial state.
-func (q queryParams) Reset() QueryParams {
-	q.values = make(map[string]string)
+// Reset resets the QueryParams to the initial state. The array style is kept.
+func (q *queryParams) Reset() QueryParams {
+	q.values = make(map[string][]string)
 	return q
 }
 
 // Len returns the number of query params.
-func (q queryParams) Len() int {
+func (q *queryParams) Len() int {
 	return len(q.values)
 }
 
+// SetArrayStyle sets how the keys with several values are encoded.
+func (q *queryParams) SetArrayStyle(style ArrayStyle) QueryParams {
+	q.style = style
+	return q
+}
+
+// ArrayStyle returns how the keys with several values are encoded.
+func (q *queryParams) ArrayStyle() ArrayStyle {
+	return q.style
+}
+
+// Encode encodes the query params, e.g. "a=1&id=1&id=2".
+func (q *queryParams) Encode() string {
+	keys := make([]string, 0, len(q.values))
+	for key := range q.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	write := func(key, value string) {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(key))
+		b.WriteByte('=')
+		b.WriteString(value)
+	}
+	for _, key := range keys {
+		values := q.values[key]
+		if len(values) == 1 {
+			write(key, url.QueryEscape(values[0]))
+			continue
+		}
+		switch q.style {
+		case ArrayStyleComma:
+			escaped := make([]string, len(values))
+			for i, value := range values {
+				escaped[i] = url.QueryEscape(value)
+			}
+			write(key, strings.Join(escaped, ","))
+		case ArrayStyleBrackets:
+			for _, value := range values {
+				write(key+"[]", url.QueryEscape(value))
+			}
+		case ArrayStyleIndexed:
+			for i, value := range values {
+				write(key+"["+strconv.Itoa(i)+"]", url.QueryEscape(value))
+			}
+		default:
+			for _, value := range values {
+				write(key, url.QueryEscape(value))
+			}
+		}
+	}
+	return b.String()
+}
+
+// SetStruct sets the tagged fields of the struct v.
+//
+//	Example:
+//		type FindOptions struct {
+//			Status []string  `query:"status"`
+//			Since  time.Time `query:"since,omitempty" layout:"2006-01-02"`
+//			Limit  *int      `query:"limit"`
+//		}
+//		err := client.QueryParams().SetStruct(FindOptions{Status: []string{"available", "sold"}})
+func (q *queryParams) SetStruct(v interface{}) error {
+	encoded, err := encodeStruct(v, queryTag)
+	if err != nil {
+		return err
+	}
+	for _, key := range encoded.keys {
+		q.values[key] = encoded.values[key]
+	}
+	return nil
+}
+
 // NewQueryParams returns a new QueryParams. It is used to add, set, get, delete and clone query params.
 func NewQueryParams() QueryParams {
 	return &queryParams{
-		values: make(map[string]string),
+		values: make(map[string][]string),
 	}
 }
@@ -0,0 +1,76 @@
+package go_requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_queryParams_Encode(t *testing.T) {
+	tests := []struct {
+		name  string
+		style ArrayStyle
+		want  string
+	}{
+		{name: "repeat", style: ArrayStyleRepeat, want: "id=1&id=2&name=dog+gie"},
+		{name: "comma", style: ArrayStyleComma, want: "id=1,2&name=dog+gie"},
+		{name: "brackets", style: ArrayStyleBrackets, want: "id%5B%5D=1&id%5B%5D=2&name=dog+gie"},
+		{name: "indexed", style: ArrayStyleIndexed, want: "id%5B0%5D=1&id%5B1%5D=2&name=dog+gie"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := NewQueryParams().
+				SetArrayStyle(tt.style).
+				Add("name", "dog gie").
+				Add("id", "1").
+				Add("id", "2")
+			if got := params.Encode(); got != tt.want {
+				t.Errorf("Encode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_queryParams(t *testing.T) {
+	params := NewQueryParams().Add("id", "1").Add("id", "2").Set("name", "a").Set("name", "b")
+	if got := params.Values(); !reflect.DeepEqual(got, map[string][]string{"id": {"1", "2"}, "name": {"b"}}) {
+		t.Errorf("Values() = %v", got)
+	}
+	if params.Get("id") != "1" || params.Get("missing") != "" {
+		t.Errorf("Get() = %v, %v", params.Get("id"), params.Get("missing"))
+	}
+	clone := params.SetArrayStyle(ArrayStyleComma).Clone()
+	params.Del("name").Add("id", "3")
+	if clone.ArrayStyle() != ArrayStyleComma || clone.Encode() != "id=1,2&name=b" {
+		t.Errorf("Clone() = %v", clone.Encode())
+	}
+	if params.Reset().Len() != 0 {
+		t.Errorf("Reset() Len() = %v, want 0", params.Len())
+	}
+}
+
+func Test_goHTTPClient_QueryParams(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+	}))
+	defer server.Close()
+
+	client := NewBuilder().Build()
+	client.QueryParams().SetArrayStyle(ArrayStyleComma).Add("id", "1").Add("id", "2")
+	if _, err := client.Get(server.URL + "?page=1"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	// the client query params are reset after every request but keep their style
+	client.QueryParams().Add("id", "3").Add("id", "4")
+	req := NewRequest(http.MethodGet, server.URL)
+	req.QueryParams().SetArrayStyle(ArrayStyleBrackets).Add("tag", "a").Add("tag", "b")
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	want := []string{"page=1&id=1,2", "id=3,4&tag%5B%5D=a&tag%5B%5D=b"}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %v, want %v", queries, want)
+	}
+}
@@ -0,0 +1,374 @@
+package go_requests
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// epochThreshold separates the rate limit reset values that are Unix times from the ones that are delays in seconds
+const epochThreshold = 1_000_000_000
+
+// ErrRateLimited is returned by the requests that a fail-fast RateLimiter rejects because no token is available.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimiter throttles the requests made by a Client with token buckets, so that they stay within the rate limits
+// of the servers. A RateLimiter is attached to a client with Builder.SetRateLimiter.
+//
+// A request takes a token from the global bucket, if any, and from the bucket of the most specific rule that matches
+// it, or else from the bucket of its host if SetHostLimit is set. When a bucket is empty, the request waits for a
+// token until its context is done, or fails with ErrRateLimited if the limiter fails fast.
+//
+//	Example:
+//		builder.SetRateLimiter(go_requests.NewRateLimiter().
+//			SetLimit(50, 10).
+//			SetRule("api.github.com", 5, 5).
+//			SetRule("api.github.com/search", 0.5, 1).
+//			SetAdaptive(true))
+type RateLimiter interface {
+	// SetLimit sets the global rate, in requests per second, and burst shared by all the requests.
+	// A rate of zero removes the global limit.
+	SetLimit(rate float64, burst int) RateLimiter
+	// SetHostLimit sets the rate and burst of every host that has no rule of its own, each host having its own bucket.
+	// A rate of zero removes the per host limit.
+	SetHostLimit(rate float64, burst int) RateLimiter
+	// SetRule sets the rate and burst of the requests that match pattern, a host optionally followed by a path prefix,
+	// e.g. "api.example.com" or "*.example.com/search". The host is matched with path.Match. When several rules
+	// match a request, the one with the longest pattern applies. A rate of zero removes the rule.
+	SetRule(pattern string, rate float64, burst int) RateLimiter
+	// SetFailFast sets whether a request fails with ErrRateLimited instead of waiting for a token.
+	SetFailFast(failFast bool) RateLimiter
+	// SetAdaptive sets whether the rates follow the X-RateLimit-* and RateLimit-* headers and the Retry-After header
+	// of the 429 responses. The configured rates remain the upper bound.
+	SetAdaptive(adaptive bool) RateLimiter
+	// Wait takes a token for the request, waiting until one is available unless the limiter fails fast.
+	Wait(req *http.Request) error
+	// Observe adapts the limits of the request from the rate limit headers of its response.
+	Observe(req *http.Request, response *Response)
+}
+
+// rateLimitRule is a rule of a rateLimiterImpl
+type rateLimitRule struct {
+	host       string
+	pathPrefix string
+	bucket     *tokenBucket
+}
+
+// rateLimiterImpl is the implementation of the RateLimiter interface
+type rateLimiterImpl struct {
+	mu       sync.Mutex
+	global   *tokenBucket
+	hostRate float64
+	// hostBurst is the burst of the buckets of the hosts, created on demand in hosts
+	hostBurst int
+	hosts     map[string]*tokenBucket
+	rules     []*rateLimitRule
+	failFast  bool
+	adaptive  bool
+	// now returns the current time, it is replaced in tests
+	now func() time.Time
+}
+
+// NewRateLimiter returns a RateLimiter without any limit. The requests wait for their tokens by default.
+func NewRateLimiter() RateLimiter {
+	return &rateLimiterImpl{
+		hosts: make(map[string]*tokenBucket),
+		now:   time.Now,
+	}
+}
+
+// SetLimit sets the global rate and burst.
+func (l *rateLimiterImpl) SetLimit(rate float64, burst int) RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.global = nil
+	if rate > 0 {
+		l.global = newTokenBucket(rate, burst, l.now())
+	}
+	return l
+}
+
+// SetHostLimit sets the rate and burst of every host.
+func (l *rateLimiterImpl) SetHostLimit(rate float64, burst int) RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hostRate, l.hostBurst = rate, burst
+	l.hosts = make(map[string]*tokenBucket)
+	return l
+}
+
+// SetRule sets the rate and burst of the requests that match pattern.
+func (l *rateLimiterImpl) SetRule(pattern string, rate float64, burst int) RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	host, pathPrefix := pattern, ""
+	if i := strings.IndexByte(pattern, '/'); i >= 0 {
+		host, pathPrefix = pattern[:i], pattern[i:]
+	}
+	host = strings.ToLower(host)
+	rules := l.rules[:0]
+	for _, rule := range l.rules {
+		if rule.host != host || rule.pathPrefix != pathPrefix {
+			rules = append(rules, rule)
+		}
+	}
+	l.rules = rules
+	if rate > 0 {
+		l.rules = append(l.rules, &rateLimitRule{host: host, pathPrefix: pathPrefix, bucket: newTokenBucket(rate, burst, l.now())})
+		// the most specific rules come first
+		sort.SliceStable(l.rules, func(i, j int) bool {
+			return len(l.rules[i].host)+len(l.rules[i].pathPrefix) > len(l.rules[j].host)+len(l.rules[j].pathPrefix)
+		})
+	}
+	return l
+}
+
+// SetFailFast sets whether a request fails with ErrRateLimited instead of waiting for a token.
+func (l *rateLimiterImpl) SetFailFast(failFast bool) RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.failFast = failFast
+	return l
+}
+
+// SetAdaptive sets whether the rates follow the rate limit headers of the responses.
+func (l *rateLimiterImpl) SetAdaptive(adaptive bool) RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.adaptive = adaptive
+	return l
+}
+
+// Wait takes a token for the request from each of its buckets. If a bucket is empty, the request waits for the
+// longest delay, and gives its tokens back if its context is done first.
+func (l *rateLimiterImpl) Wait(req *http.Request) error {
+	l.mu.Lock()
+	buckets := l.buckets(req, false)
+	failFast := l.failFast
+	l.mu.Unlock()
+	now := l.now()
+	var delay time.Duration
+	for i, bucket := range buckets {
+		wait, ok := bucket.reserve(now, failFast)
+		if !ok {
+			for _, reserved := range buckets[:i] {
+				reserved.cancel()
+			}
+			return ErrRateLimited
+		}
+		if wait > delay {
+			delay = wait
+		}
+	}
+	if err := sleep(req.Context(), delay); err != nil {
+		for _, bucket := range buckets {
+			bucket.cancel()
+		}
+		return err
+	}
+	return nil
+}
+
+// Observe adapts the bucket of the request to the rate limit headers of its response.
+// The bucket of the host is created from the headers if the request has none.
+func (l *rateLimiterImpl) Observe(req *http.Request, response *Response) {
+	if response == nil {
+		return
+	}
+	l.mu.Lock()
+	if !l.adaptive {
+		l.mu.Unlock()
+		return
+	}
+	buckets := l.buckets(req, true)
+	l.mu.Unlock()
+	if len(buckets) == 0 {
+		return
+	}
+	// the last bucket is the most specific one
+	bucket := buckets[len(buckets)-1]
+	now := l.now()
+	if response.StatusCode() == http.StatusTooManyRequests {
+		if wait, ok := parseRetryAfter(response.Header().Get("Retry-After")); ok {
+			bucket.pause(now.Add(wait))
+			return
+		}
+	}
+	remaining, reset, ok := parseRateLimitHeaders(response.Header(), now)
+	if !ok {
+		return
+	}
+	if remaining <= 0 {
+		bucket.pause(now.Add(reset))
+		return
+	}
+	if reset > 0 {
+		bucket.adapt(float64(remaining)/reset.Seconds(), now)
+	}
+}
+
+// buckets returns the buckets a request takes its tokens from, the global one first. If create is true and the
+// request has no bucket but the global one, a bucket is created for its host. l.mu must be held.
+func (l *rateLimiterImpl) buckets(req *http.Request, create bool) []*tokenBucket {
+	var buckets []*tokenBucket
+	if l.global != nil {
+		buckets = append(buckets, l.global)
+	}
+	host := strings.ToLower(req.URL.Hostname())
+	for _, rule := range l.rules {
+		if matched, _ := path.Match(rule.host, host); matched && strings.HasPrefix(req.URL.Path, rule.pathPrefix) {
+			return append(buckets, rule.bucket)
+		}
+	}
+	if bucket, ok := l.hosts[host]; ok {
+		return append(buckets, bucket)
+	}
+	switch {
+	case l.hostRate > 0:
+		bucket := newTokenBucket(l.hostRate, l.hostBurst, l.now())
+		l.hosts[host] = bucket
+		return append(buckets, bucket)
+	case create:
+		// an adaptive bucket without a configured rate, which only follows the headers
+		bucket := newTokenBucket(math.Inf(1), 1, l.now())
+		l.hosts[host] = bucket
+		return append(buckets, bucket)
+	}
+	return buckets
+}
+
+// tokenBucket is a token bucket that is refilled at rate tokens per second, up to burst tokens.
+// Tokens may be reserved in advance, in which case the number of tokens is negative.
+type tokenBucket struct {
+	mu sync.Mutex
+	// rate is the current rate, maxRate the configured one
+	rate    float64
+	maxRate float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	// pausedUntil is when a server that ran out of quota accepts requests again
+	pausedUntil time.Time
+}
+
+// newTokenBucket returns a full bucket. A burst lower than 1 is treated as 1.
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, maxRate: rate, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+// refill adds the tokens earned since the last refill. b.mu must be held.
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it. If failFast is true, the token is only
+// taken if it can be used right away.
+func (b *tokenBucket) reserve(now time.Time, failFast bool) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	wait := time.Duration(0)
+	if b.tokens < 1 {
+		wait = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	}
+	if paused := b.pausedUntil.Sub(now); paused > wait {
+		wait = paused
+	}
+	if failFast && wait > 0 {
+		return 0, false
+	}
+	b.tokens--
+	return wait, true
+}
+
+// cancel gives back a token taken by reserve.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// pause stops the bucket from giving tokens until the given time.
+func (b *tokenBucket) pause(until time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// adapt sets the rate of the bucket, bounded by the configured rate.
+func (b *tokenBucket) adapt(rate float64, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	b.rate = math.Min(rate, b.maxRate)
+}
+
+// parseRateLimitHeaders returns the remaining quota of a response and the delay before it is reset, from either
+// the X-RateLimit-Remaining and X-RateLimit-Reset headers, the RateLimit-Remaining and RateLimit-Reset headers,
+// or the RateLimit header with its remaining and reset parameters, e.g. "limit=100, remaining=50, reset=30".
+// A reset greater than 1e9 is a Unix time, otherwise it is a number of seconds.
+func parseRateLimitHeaders(header http.Header, now time.Time) (int, time.Duration, bool) {
+	var remaining, reset string
+	for _, prefix := range []string{"X-Ratelimit-", "Ratelimit-"} {
+		if value := header.Get(prefix + "Remaining"); value != "" {
+			remaining, reset = value, header.Get(prefix+"Reset")
+			break
+		}
+	}
+	if remaining == "" {
+		for _, param := range strings.FieldsFunc(header.Get("Ratelimit"), func(r rune) bool { return r == ',' || r == ';' }) {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			switch strings.ToLower(name) {
+			case "remaining", "r":
+				remaining = value
+			case "reset", "t":
+				reset = value
+			}
+		}
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(remaining))
+	if err != nil {
+		return 0, 0, false
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(reset), 64)
+	if err != nil || seconds < 0 {
+		return count, 0, true
+	}
+	if seconds > epochThreshold {
+		wait := time.Unix(int64(seconds), 0).Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return count, wait, true
+	}
+	return count, time.Duration(seconds * float64(time.Second)), true
+}
+
+// rateLimitMiddleware returns the middleware that takes a token from the limiter before every attempt of a request
+// and adapts the limiter to the response.
+func rateLimitMiddleware(limiter RateLimiter) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			if err := limiter.Wait(req); err != nil {
+				return nil, err
+			}
+			response, err := next(req)
+			limiter.Observe(req, response)
+			return response, err
+		}
+	}
+}
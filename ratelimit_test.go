@@ -0,0 +1,168 @@
+package go_requests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_tokenBucket_reserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(2, 2, now)
+	for i, want := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if got, ok := bucket.reserve(now, false); !ok || got != want {
+			t.Errorf("reserve() #%d = %v, %v, want %v, true", i, got, ok, want)
+		}
+	}
+	if _, ok := bucket.reserve(now, true); ok {
+		t.Error("reserve() fail fast on an empty bucket = true, want false")
+	}
+	// the two reserved tokens are earned back after one second
+	now = now.Add(1500 * time.Millisecond)
+	if got, ok := bucket.reserve(now, true); !ok || got != 0 {
+		t.Errorf("reserve() after refill = %v, %v, want 0, true", got, ok)
+	}
+}
+
+func Test_rateLimiterImpl_buckets(t *testing.T) {
+	limiter := NewRateLimiter().
+		SetLimit(100, 10).
+		SetHostLimit(10, 1).
+		SetRule("api.example.com", 5, 1).
+		SetRule("*.example.com/search", 1, 1).(*rateLimiterImpl)
+	rule := func(pattern string) *tokenBucket {
+		for _, rule := range limiter.rules {
+			if rule.host+rule.pathPrefix == pattern {
+				return rule.bucket
+			}
+		}
+		return nil
+	}
+	tests := []struct {
+		url  string
+		want *tokenBucket
+	}{
+		{url: "https://api.example.com/pets", want: rule("api.example.com")},
+		{url: "https://API.example.com/search?q=dogs", want: rule("*.example.com/search")},
+		{url: "https://www.example.com/search", want: rule("*.example.com/search")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			buckets := limiter.buckets(req, false)
+			if len(buckets) != 2 || buckets[0] != limiter.global || buckets[1] != tt.want {
+				t.Errorf("buckets() = %v, want the global bucket and %v", buckets, tt.want)
+			}
+		})
+	}
+	other := limiter.buckets(httptest.NewRequest(http.MethodGet, "https://other.example.org/", nil), false)
+	if len(other) != 2 || other[1] != limiter.hosts["other.example.org"] {
+		t.Errorf("buckets() = %v, want the global bucket and the one of the host", other)
+	}
+	limiter.SetRule("api.example.com", 0, 0)
+	if got := rule("api.example.com"); got != nil {
+		t.Error("SetRule() with a zero rate did not remove the rule")
+	}
+}
+
+func Test_rateLimiterImpl_Wait_canceled(t *testing.T) {
+	limiter := NewRateLimiter().SetLimit(0.001, 1).(*rateLimiterImpl)
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+	if err := limiter.Wait(req); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(req.WithContext(ctx)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	// the token of the canceled request is given back
+	if limiter.global.tokens < -0.01 {
+		t.Errorf("tokens = %v, want the canceled reservation given back", limiter.global.tokens)
+	}
+}
+
+func Test_rateLimiterImpl_Observe(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		status   int
+		header   http.Header
+		wantWait time.Duration
+	}{
+		{name: "exhausted", status: http.StatusOK, header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"30"}}, wantWait: 30 * time.Second},
+		{name: "epoch reset", status: http.StatusOK, header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(now.Add(time.Minute).Unix(), 10)}}, wantWait: time.Minute},
+		{name: "slowed down", status: http.StatusOK, header: http.Header{"Ratelimit-Remaining": {"5"}, "Ratelimit-Reset": {"10"}}, wantWait: 2 * time.Second},
+		{name: "structured header", status: http.StatusOK, header: http.Header{"Ratelimit": {"limit=100, remaining=0, reset=5"}}, wantWait: 5 * time.Second},
+		{name: "retry after", status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"7"}}, wantWait: 7 * time.Second},
+		{name: "no headers", status: http.StatusOK, header: http.Header{}, wantWait: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter().SetAdaptive(true).(*rateLimiterImpl)
+			limiter.now = func() time.Time { return now }
+			req := httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+			limiter.Observe(req, NewResponse(tt.status, tt.header, nil))
+			bucket := limiter.hosts["api.example.com"]
+			if bucket == nil {
+				t.Fatal("Observe() did not create the bucket of the host")
+			}
+			// a paused bucket delays the first request, a slowed down one the second, once its token is used
+			first, _ := bucket.reserve(now, false)
+			second, _ := bucket.reserve(now, false)
+			wait := first
+			if second > wait {
+				wait = second
+			}
+			if wait != tt.wantWait {
+				t.Errorf("reserve() = %v, %v, want a wait of %v", first, second, tt.wantWait)
+			}
+		})
+	}
+}
+
+func Test_parseRateLimitHeaders(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name          string
+		header        http.Header
+		wantRemaining int
+		wantReset     time.Duration
+		wantOK        bool
+	}{
+		{name: "x-ratelimit", header: http.Header{"X-Ratelimit-Remaining": {"10"}, "X-Ratelimit-Reset": {"1700000060"}}, wantRemaining: 10, wantReset: time.Minute, wantOK: true},
+		{name: "ratelimit", header: http.Header{"Ratelimit-Remaining": {"3"}, "Ratelimit-Reset": {"1.5"}}, wantRemaining: 3, wantReset: 1500 * time.Millisecond, wantOK: true},
+		{name: "structured", header: http.Header{"Ratelimit": {`"default";r=4;t=20`}}, wantRemaining: 4, wantReset: 20 * time.Second, wantOK: true},
+		{name: "no reset", header: http.Header{"X-Ratelimit-Remaining": {"2"}}, wantRemaining: 2, wantOK: true},
+		{name: "none", header: http.Header{}, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining, reset, ok := parseRateLimitHeaders(tt.header, now)
+			if remaining != tt.wantRemaining || reset != tt.wantReset || ok != tt.wantOK {
+				t.Errorf("parseRateLimitHeaders() = %v, %v, %v, want %v, %v, %v",
+					remaining, reset, ok, tt.wantRemaining, tt.wantReset, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_goHTTPClient_rateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	builder := NewBuilder()
+	builder.SetRateLimiter(NewRateLimiter().SetHostLimit(0.001, 2).SetFailFast(true))
+	client := builder.Build()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(server.URL); err != nil {
+			t.Fatalf("Get() #%d error = %v", i, err)
+		}
+	}
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Get() error = %v, want %v", err, ErrRateLimited)
+	}
+}
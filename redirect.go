@@ -0,0 +1,163 @@
+package go_requests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultMaxRedirects is the default maximum number of redirects followed for a request, as net/http does
+const defaultMaxRedirects = 10
+
+// ErrTooManyRedirects is returned, wrapped, when a request is redirected more times than a RedirectPolicy allows.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// credentialHeaders are the headers removed from a request redirected to another origin
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// Redirect is a redirect that was followed to get a response, see Response.Redirects.
+type Redirect struct {
+	// URL is the URL of the request that was redirected.
+	URL string
+	// StatusCode is the status code of the redirect response, e.g. 302.
+	StatusCode int
+}
+
+// RedirectPolicy is the interface that decides which redirects the Client follows.
+// A RedirectPolicy is attached to a client with Builder.SetRedirectPolicy. Without a policy,
+// the redirects are followed as net/http does.
+//
+//	Example:
+//		builder.SetRedirectPolicy(go_requests.NewRedirectPolicy().
+//			SetMaxRedirects(3).
+//			SetSameHostOnly(true))
+type RedirectPolicy interface {
+	// SetMaxRedirects sets the maximum number of redirects followed for a request. Once it is reached,
+	// the request fails with ErrTooManyRedirects. Zero disables the redirects.
+	SetMaxRedirects(redirects int) RedirectPolicy
+	// SetFollow sets whether redirects are followed. When they are not, the redirect response is returned as it is.
+	SetFollow(follow bool) RedirectPolicy
+	// SetSameHostOnly sets whether only the redirects to the host of the first request are followed.
+	// A redirect to another host returns the redirect response as it is.
+	SetSameHostOnly(sameHostOnly bool) RedirectPolicy
+	// SetStripCredentials sets whether the Authorization, Proxy-Authorization and Cookie headers are removed
+	// when a request is redirected to another origin (scheme, host and port). It is true by default.
+	// When it is false, the Authorization header is sent to every host, while cookies are still only sent
+	// where net/http and the cookie jar allow.
+	SetStripCredentials(strip bool) RedirectPolicy
+	// CheckRedirect is the http.Client CheckRedirect function of the policy.
+	// req is the request about to be sent and via the requests already sent, oldest first.
+	CheckRedirect(req *http.Request, via []*http.Request) error
+}
+
+// redirectPolicyImpl is the default implementation of the RedirectPolicy interface
+type redirectPolicyImpl struct {
+	maxRedirects     int
+	follow           bool
+	sameHostOnly     bool
+	stripCredentials bool
+}
+
+// NewRedirectPolicy returns a new RedirectPolicy with the default values.
+//
+//   - up to 10 redirects
+//   - redirects to any host are followed
+//   - credentials are stripped on redirects to another origin
+func NewRedirectPolicy() RedirectPolicy {
+	return &redirectPolicyImpl{
+		maxRedirects:     defaultMaxRedirects,
+		follow:           true,
+		stripCredentials: true,
+	}
+}
+
+// SetMaxRedirects sets the maximum number of redirects followed for a request.
+func (p *redirectPolicyImpl) SetMaxRedirects(redirects int) RedirectPolicy {
+	if redirects < 0 {
+		redirects = 0
+	}
+	p.maxRedirects = redirects
+	return p
+}
+
+// SetFollow sets whether redirects are followed.
+func (p *redirectPolicyImpl) SetFollow(follow bool) RedirectPolicy {
+	p.follow = follow
+	return p
+}
+
+// SetSameHostOnly sets whether only the redirects to the host of the first request are followed.
+func (p *redirectPolicyImpl) SetSameHostOnly(sameHostOnly bool) RedirectPolicy {
+	p.sameHostOnly = sameHostOnly
+	return p
+}
+
+// SetStripCredentials sets whether the credentials are removed on redirects to another origin.
+func (p *redirectPolicyImpl) SetStripCredentials(strip bool) RedirectPolicy {
+	p.stripCredentials = strip
+	return p
+}
+
+// CheckRedirect is the http.Client CheckRedirect function of the policy.
+// net/http already drops the Authorization and Cookie headers on redirects to a host that is not the host of
+// the first request or one of its subdomains; when stripping is disabled, the Authorization header is put back.
+func (p *redirectPolicyImpl) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if !p.follow || p.maxRedirects == 0 {
+		return http.ErrUseLastResponse
+	}
+	if len(via) > p.maxRedirects {
+		return fmt.Errorf("stopped after %d redirects: %w", p.maxRedirects, ErrTooManyRedirects)
+	}
+	first := via[0]
+	if p.sameHostOnly && !strings.EqualFold(req.URL.Hostname(), first.URL.Hostname()) {
+		return http.ErrUseLastResponse
+	}
+	previous := via[len(via)-1]
+	if p.stripCredentials {
+		if !sameOrigin(req, previous) {
+			for _, header := range credentialHeaders {
+				req.Header.Del(header)
+			}
+		}
+		return nil
+	}
+	if _, ok := req.Header["Authorization"]; !ok && len(previous.Header["Authorization"]) > 0 {
+		req.Header["Authorization"] = append([]string(nil), previous.Header["Authorization"]...)
+	}
+	return nil
+}
+
+// sameOrigin returns true if a and b have the same scheme, host and port.
+func sameOrigin(a, b *http.Request) bool {
+	return strings.EqualFold(a.URL.Scheme, b.URL.Scheme) && strings.EqualFold(canonicalHost(a), canonicalHost(b))
+}
+
+// canonicalHost returns the host and port of the URL of the request, with the default port of its scheme.
+func canonicalHost(req *http.Request) string {
+	port := req.URL.Port()
+	if port == "" {
+		port = "80"
+		if strings.EqualFold(req.URL.Scheme, "https") {
+			port = "443"
+		}
+	}
+	return req.URL.Hostname() + ":" + port
+}
+
+// redirectChain returns the redirects followed to get the response, oldest first.
+// Every request created by a redirect keeps the response that caused it.
+func redirectChain(response *http.Response) []Redirect {
+	var chain []Redirect
+	for req := response.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		redirected := req.Response.Request
+		if redirected == nil {
+			break
+		}
+		chain = append(chain, Redirect{URL: redirected.URL.String(), StatusCode: req.Response.StatusCode})
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
@@ -0,0 +1,135 @@
+package go_requests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_goHTTPClient_redirects(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("auth=" + r.Header.Get("Authorization")))
+	}))
+	defer other.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusMovedPermanently)
+		case "/c":
+			_, _ = w.Write([]byte("auth=" + r.Header.Get("Authorization")))
+		case "/other":
+			http.Redirect(w, r, other.URL+"/landing", http.StatusTemporaryRedirect)
+		case "/localhost":
+			http.Redirect(w, r, strings.Replace(other.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+		}
+	}))
+	defer server.Close()
+	authorization := http.Header{"Authorization": []string{"Bearer token"}}
+
+	tests := []struct {
+		name          string
+		policy        RedirectPolicy
+		path          string
+		wantStatus    int
+		wantBody      string
+		wantRedirects []Redirect
+		wantErr       error
+	}{
+		{
+			name:       "default",
+			path:       "/a",
+			wantStatus: http.StatusOK,
+			wantBody:   "auth=Bearer token",
+			wantRedirects: []Redirect{
+				{URL: server.URL + "/a", StatusCode: http.StatusFound},
+				{URL: server.URL + "/b", StatusCode: http.StatusMovedPermanently},
+			},
+		},
+		{
+			name:       "disabled",
+			policy:     NewRedirectPolicy().SetFollow(false),
+			path:       "/a",
+			wantStatus: http.StatusFound,
+		},
+		{
+			name:       "no redirects",
+			policy:     NewRedirectPolicy().SetMaxRedirects(0),
+			path:       "/a",
+			wantStatus: http.StatusFound,
+		},
+		{
+			name:    "too many redirects",
+			policy:  NewRedirectPolicy().SetMaxRedirects(1),
+			path:    "/a",
+			wantErr: ErrTooManyRedirects,
+		},
+		{
+			name:          "strip credentials",
+			policy:        NewRedirectPolicy(),
+			path:          "/other",
+			wantStatus:    http.StatusOK,
+			wantBody:      "auth=",
+			wantRedirects: []Redirect{{URL: server.URL + "/other", StatusCode: http.StatusTemporaryRedirect}},
+		},
+		{
+			name:          "keep credentials",
+			policy:        NewRedirectPolicy().SetStripCredentials(false),
+			path:          "/other",
+			wantStatus:    http.StatusOK,
+			wantBody:      "auth=Bearer token",
+			wantRedirects: []Redirect{{URL: server.URL + "/other", StatusCode: http.StatusTemporaryRedirect}},
+		},
+		{
+			name:       "same host only",
+			policy:     NewRedirectPolicy().SetSameHostOnly(true),
+			path:       "/localhost",
+			wantStatus: http.StatusFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder()
+			if tt.policy != nil {
+				builder.SetRedirectPolicy(tt.policy)
+			}
+			response, err := builder.Build().Get(server.URL+tt.path, authorization)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Get() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode() != tt.wantStatus {
+				t.Errorf("StatusCode() = %d, want %d", response.StatusCode(), tt.wantStatus)
+			}
+			if tt.wantBody != "" && response.String() != tt.wantBody {
+				t.Errorf("String() = %q, want %q", response.String(), tt.wantBody)
+			}
+			if !reflect.DeepEqual(response.Redirects(), tt.wantRedirects) {
+				t.Errorf("Redirects() = %v, want %v", response.Redirects(), tt.wantRedirects)
+			}
+		})
+	}
+}
+
+func Test_goHTTPClient_redirectPolicy_customClient(t *testing.T) {
+	custom := &http.Client{}
+	builder := NewBuilder()
+	builder.SetHTTPClient(custom)
+	builder.SetRedirectPolicy(NewRedirectPolicy())
+	client := builder.Build().(*goHTTPClient)
+	if client.getClient().CheckRedirect == nil {
+		t.Error("CheckRedirect = nil, want the policy")
+	}
+	if custom.CheckRedirect != nil {
+		t.Error("the custom http client was modified")
+	}
+}
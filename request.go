@@ -0,0 +1,267 @@
+package go_requests
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// Request is a single HTTP request, built for one call and sent with Client.Do or Client.DoWithContext.
+// Unlike the query params of the Client, everything set on a Request only applies to that request,
+// so that a Client can be shared by goroutines that send requests with different parameters.
+//
+//	Example:
+//		req := go_requests.NewRequest(http.MethodGet, "https://petstore.swagger.io/v2/pet/{petId}").
+//			SetPathParam("petId", "42").
+//			SetHeader("Accept", "application/json").
+//			SetTimeout(2 * time.Second)
+//		response, err := client.Do(req)
+type Request interface {
+	// Method returns the method of the request.
+	Method() Method
+	// URL returns the URL of the request, without the query params set with QueryParams.
+	URL() string
+	// SetHeader sets a header of the request. It takes precedence over the headers of the Client.
+	SetHeader(key, value string) Request
+	// SetHeaders sets every header of headers on the request, replacing the values already set for the same keys.
+	SetHeaders(headers http.Header) Request
+	// Headers returns the headers of the request.
+	Headers() http.Header
+	// QueryParams returns the query params of the request, which are added to the query of the URL.
+	QueryParams() QueryParams
+	// SetPathParam sets the value of a {name} parameter of the path of the URL, see ExpandPath.
+	SetPathParam(name, value string) Request
+	// SetPathParams sets the values of several parameters of the path of the URL.
+	SetPathParams(params map[string]string) Request
+	// SetTemplateValues expands the URL as an RFC 6570 URI template with the given values, see URITemplate.
+	// It takes precedence over the path params.
+	SetTemplateValues(values map[string]interface{}) Request
+	// SetBody sets the body of the request.
+	SetBody(body []byte) Request
+	// SetBodyReader sets a body that is read while the request is sent, see Client.Upload.
+	SetBodyReader(body io.Reader, contentLength int64) Request
+	// SetMultipart sets a multipart/form-data body, see Client.PostMultipart.
+	SetMultipart(body Multipart) Request
+	// SetValue sets a value encoded according to the Content-Type of the request, see Client.PostValue.
+	SetValue(v interface{}) Request
+	// SetAuthorization sets the authorization of the request. It takes precedence over the one of the Builder.
+	SetAuthorization(authorization Authorization) Request
+	// SetTimeout sets how long the request may take, including reading the response body.
+	// It applies in addition to the timeouts of the Client. Zero means no timeout of its own.
+	SetTimeout(timeout time.Duration) Request
+	// SetStream makes the response body readable from the connection instead of being buffered, see Client.Stream.
+	SetStream(stream bool) Request
+	// SetHedgePolicy sets the policy that hedges the request. It takes precedence over the one of the Builder.
+	SetHedgePolicy(policy HedgePolicy) Request
+}
+
+// requestImpl is the implementation of the Request interface
+type requestImpl struct {
+	method      Method
+	url         string
+	headers     http.Header
+	queryParams QueryParams
+	pathParams  map[string]string
+	// templateValues expand the URL as a URI template if it is not nil
+	templateValues map[string]interface{}
+	// rawBody is the body set with SetBody, read with a new reader every time the request is sent
+	rawBody []byte
+	// body is the body of the request, read when it is sent
+	body          io.Reader
+	contentLength int64
+	multipart     Multipart
+	// value is the value encoded as the body if hasValue is true
+	value         interface{}
+	hasValue      bool
+	authorization Authorization
+	timeout       time.Duration
+	stream        bool
+	hedgePolicy   HedgePolicy
+}
+
+// NewRequest returns a new Request with the given method and URL.
+func NewRequest(method Method, url string) Request {
+	return &requestImpl{
+		method:      method,
+		url:         url,
+		headers:     make(http.Header),
+		queryParams: NewQueryParams(),
+	}
+}
+
+// Method returns the method of the request.
+func (r *requestImpl) Method() Method {
+	return r.method
+}
+
+// URL returns the URL of the request.
+func (r *requestImpl) URL() string {
+	return r.url
+}
+
+// SetHeader sets a header of the request.
+func (r *requestImpl) SetHeader(key, value string) Request {
+	r.headers.Set(key, value)
+	return r
+}
+
+// SetHeaders sets every header of headers on the request.
+func (r *requestImpl) SetHeaders(headers http.Header) Request {
+	for key := range headers {
+		r.headers.Del(key)
+	}
+	for key, values := range headers {
+		for _, value := range values {
+			r.headers.Add(key, value)
+		}
+	}
+	return r
+}
+
+// Headers returns the headers of the request.
+func (r *requestImpl) Headers() http.Header {
+	return r.headers
+}
+
+// QueryParams returns the query params of the request.
+func (r *requestImpl) QueryParams() QueryParams {
+	return r.queryParams
+}
+
+// SetPathParam sets the value of a parameter of the path of the URL.
+func (r *requestImpl) SetPathParam(name, value string) Request {
+	if r.pathParams == nil {
+		r.pathParams = make(map[string]string)
+	}
+	r.pathParams[name] = value
+	return r
+}
+
+// SetPathParams sets the values of several parameters of the path of the URL.
+func (r *requestImpl) SetPathParams(params map[string]string) Request {
+	for name, value := range params {
+		r.SetPathParam(name, value)
+	}
+	return r
+}
+
+// SetTemplateValues expands the URL as an RFC 6570 URI template.
+//
+//	Example:
+//		req := go_requests.NewRequest(http.MethodGet, "/pet/findByTags{?tags*}").
+//			SetTemplateValues(map[string]interface{}{"tags": []string{"dogs", "cats"}})
+func (r *requestImpl) SetTemplateValues(values map[string]interface{}) Request {
+	if r.templateValues == nil {
+		r.templateValues = make(map[string]interface{}, len(values))
+	}
+	for name, value := range values {
+		r.templateValues[name] = value
+	}
+	return r
+}
+
+// resolvedURL returns the URL of the request with its template or path params expanded, resolved against baseURL.
+func (r *requestImpl) resolvedURL(baseURL string) (string, error) {
+	rawURL := r.url
+	var err error
+	switch {
+	case r.templateValues != nil:
+		if rawURL, err = ExpandURITemplate(rawURL, r.templateValues); err != nil {
+			return "", err
+		}
+	case len(r.pathParams) > 0:
+		if rawURL, err = ExpandPath(rawURL, r.pathParams); err != nil {
+			return "", err
+		}
+	}
+	return resolveURL(baseURL, rawURL)
+}
+
+// SetBody sets the body of the request. A nil body sends no body. The request can be sent several times.
+func (r *requestImpl) SetBody(body []byte) Request {
+	r.resetBody()
+	r.rawBody = body
+	r.contentLength = int64(len(body))
+	return r
+}
+
+// SetBodyReader sets a body that is read while the request is sent.
+func (r *requestImpl) SetBodyReader(body io.Reader, contentLength int64) Request {
+	r.resetBody()
+	r.body = body
+	r.contentLength = contentLength
+	return r
+}
+
+// SetMultipart sets a multipart/form-data body.
+func (r *requestImpl) SetMultipart(body Multipart) Request {
+	r.resetBody()
+	r.multipart = body
+	return r
+}
+
+// SetValue sets a value encoded according to the Content-Type of the request.
+func (r *requestImpl) SetValue(v interface{}) Request {
+	r.resetBody()
+	r.value = v
+	r.hasValue = true
+	return r
+}
+
+// SetAuthorization sets the authorization of the request.
+func (r *requestImpl) SetAuthorization(authorization Authorization) Request {
+	r.authorization = authorization
+	return r
+}
+
+// SetTimeout sets how long the request may take.
+func (r *requestImpl) SetTimeout(timeout time.Duration) Request {
+	r.timeout = timeout
+	return r
+}
+
+// SetStream makes the response body readable from the connection.
+func (r *requestImpl) SetStream(stream bool) Request {
+	r.stream = stream
+	return r
+}
+
+// SetHedgePolicy sets the policy that hedges the request.
+//
+//	Example:
+//		req := go_requests.NewRequest(http.MethodGet, "/pet/42").
+//			SetHedgePolicy(go_requests.NewHedgePolicy(20 * time.Millisecond).SetMaxRequests(3))
+func (r *requestImpl) SetHedgePolicy(policy HedgePolicy) Request {
+	r.hedgePolicy = policy
+	return r
+}
+
+// resetBody drops the body set previously, so that the last body set wins.
+func (r *requestImpl) resetBody() {
+	r.rawBody = nil
+	r.body = nil
+	r.contentLength = 0
+	r.multipart = nil
+	r.value = nil
+	r.hasValue = false
+}
+
+// newMethodRequest returns the Request sent by the methods of the Client that take a []byte body.
+func newMethodRequest(method Method, url string, body []byte, headers ...http.Header) *requestImpl {
+	req := NewRequest(method, url).SetHeaders(getHeader(headers...)).SetBody(body)
+	return req.(*requestImpl)
+}
+
+// cancelReadCloser is the body of a streamed response whose request has its own timeout.
+// The timeout is released when the body is closed.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel func()
+}
+
+// Close closes the body and releases the timeout of the request.
+func (c *cancelReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,154 @@
+package go_requests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_goHTTPClient_Do(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s %s %s %s %s %s", r.Method, r.URL.RawQuery, r.Header.Get("Accept"),
+			r.Header.Get("Authorization"), r.Header.Get("Content-Type"), body)
+	}))
+	defer server.Close()
+
+	builder := NewBuilder()
+	builder.Headers().SetAccept("application/xml").SetContentType("application/json")
+	builder.SetAuthorization(NewAuthorization().Bearer("client"))
+	client := builder.Build()
+	tests := []struct {
+		name    string
+		request func() Request
+		want    string
+		wantErr error
+	}{
+		{
+			name: "client defaults",
+			request: func() Request {
+				return NewRequest(http.MethodGet, server.URL)
+			},
+			want: "GET  application/xml Bearer client application/json ",
+		},
+		{
+			name: "request overrides",
+			request: func() Request {
+				req := NewRequest(http.MethodPost, server.URL+"?a=1").
+					SetHeader("Accept", "text/plain").
+					SetAuthorization(NewAuthorization().Bearer("request")).
+					SetValue(map[string]int{"id": 1})
+				req.QueryParams().Set("b", "2")
+				return req
+			},
+			want: `POST a=1&b=2 text/plain Bearer request application/json {"id":1}`,
+		},
+		{
+			name: "last body wins",
+			request: func() Request {
+				return NewRequest(http.MethodPut, server.URL).
+					SetValue("ignored").
+					SetBody([]byte("raw"))
+			},
+			want: "PUT  application/xml Bearer client application/json raw",
+		},
+		{
+			name: "timeout",
+			request: func() Request {
+				return NewRequest(http.MethodGet, server.URL+"/slow").SetTimeout(20 * time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.Do(tt.request())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && res.String() != tt.want {
+				t.Errorf("Do() = %q, want %q", res.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_goHTTPClient_Do_concurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Query().Get("id")))
+	}))
+	defer server.Close()
+
+	client := NewBuilder().Build()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			req := NewRequest(http.MethodGet, server.URL)
+			req.QueryParams().Set("id", id)
+			res, err := client.Do(req)
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+				return
+			}
+			if res.String() != id {
+				t.Errorf("Do() = %v, want %v", res.String(), id)
+			}
+		}(fmt.Sprint(i))
+	}
+	wg.Wait()
+}
+
+func Test_goHTTPClient_Do_reuse(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, string(body))
+	}))
+	defer server.Close()
+
+	client := NewBuilder().Build()
+	req := NewRequest(http.MethodPost, server.URL).SetBody([]byte("hello"))
+	for i := 0; i < 2; i++ {
+		if _, err := client.Do(req); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+	if fmt.Sprint(got) != "[hello hello]" {
+		t.Errorf("bodies = %q, want %q", got, []string{"hello", "hello"})
+	}
+}
+
+func Test_goHTTPClient_Do_streamTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("streamed"))
+	}))
+	defer server.Close()
+
+	res, err := NewBuilder().Build().Do(NewRequest(http.MethodGet, server.URL).SetStream(true).SetTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	// the timeout of the request must not cancel the body before it is read
+	body, err := io.ReadAll(res.Body())
+	if err != nil || string(body) != "streamed" {
+		t.Errorf("Body() = %q, %v", body, err)
+	}
+	if err = res.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
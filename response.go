@@ -1,10 +1,14 @@
 package go_requests
 
 import (
+	"bytes"
 	"encoding"
 	"encoding/json"
 	"encoding/xml"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v2"
@@ -22,6 +26,8 @@ const (
 	yamlContentType ContentType = "application/yaml"
 	// textContentType is the content type for text.
 	textContentType ContentType = "text/plain"
+	// formContentType is the content type for url encoded forms.
+	formContentType ContentType = "application/x-www-form-urlencoded"
 	// noneContentType is the content type for none.
 	noneContentType ContentType = ""
 )
@@ -36,12 +42,47 @@ const (
 //   - The HTTP status code can be retrieved using the StatusCode method.
 //   - The HTTP header can be retrieved using the Header method.
 //   - The HTTP status can be retrieved using the Status method.
+//   - The number of attempts it took to get the response can be retrieved using the Attempts method.
+//   - The redirects followed to get the response can be retrieved using the Redirects method.
+//   - Whether the response was answered by the cache can be retrieved using the FromCache and Revalidated methods.
+//   - The links of the Link header can be retrieved using the Links and Link methods.
+//   - A streamed response (see Client.Stream) exposes the unread body using the Body method and must be closed using the Close method.
 type Response struct {
 	statusCode  int
 	status      string
 	header      http.Header
 	body        []byte
 	contentType string
+	attempts    int
+	// url is the URL of the request that returned the response, the links of the response are relative to it
+	url *url.URL
+	// redirects are the redirects followed to get the response
+	redirects []Redirect
+	// fromCache is true if the response was answered by the cache, revalidated if the server confirmed it first
+	fromCache   bool
+	revalidated bool
+	// stream is the unread body of a streamed response. It is nil once the body has been buffered.
+	stream io.ReadCloser
+}
+
+// NewResponse returns a Response with the given status code, header and body.
+// It is meant for middlewares that answer a request without sending it, e.g. from a cache or in tests.
+func NewResponse(statusCode int, header http.Header, body []byte) *Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+	status := strconv.Itoa(statusCode)
+	if text := http.StatusText(statusCode); text != "" {
+		status += " " + text
+	}
+	return &Response{
+		statusCode:  statusCode,
+		status:      status,
+		header:      header,
+		body:        body,
+		contentType: header.Get("Content-Type"),
+		attempts:    1,
+	}
 }
 
 // StatusCode returns the HTTP status code of the response.
@@ -54,35 +95,109 @@ func (r *Response) Header() http.Header {
 	return r.header
 }
 
+// Attempts returns how many times the request was sent before this response was received.
+// It is greater than 1 only when a RetryPolicy is configured on the Builder.
+func (r *Response) Attempts() int {
+	return r.attempts
+}
+
+// Redirects returns the redirects that were followed to get the response, oldest first, see RedirectPolicy.
+// It is empty if the request was not redirected.
+func (r *Response) Redirects() []Redirect {
+	return r.redirects
+}
+
+// FromCache returns true if the response was answered by the cache of the Client, see Builder.SetCache.
+func (r *Response) FromCache() bool {
+	return r.fromCache
+}
+
+// Revalidated returns true if the response was answered by the cache after the server confirmed, with a
+// 304 Not Modified response, that it was still valid.
+func (r *Response) Revalidated() bool {
+	return r.revalidated
+}
+
 // Bytes returns the response body in []byte format.
+// The body of a streamed response is read until EOF and closed first. If reading fails,
+// the part read so far is returned; use Unmarshal or Body to get the error.
 func (r *Response) Bytes() []byte {
+	_ = r.buffer()
 	return r.body
 }
 
+// Body returns the response body as an io.ReadCloser.
+// For a streamed response it is the body read from the connection, which the caller must close.
+// For a buffered response it reads from the buffered body and closing it is a no-op.
+func (r *Response) Body() io.ReadCloser {
+	if r.stream != nil {
+		return r.stream
+	}
+	return io.NopCloser(bytes.NewReader(r.body))
+}
+
+// Close closes the body of a streamed response. It is safe to call on buffered responses and more than once.
+func (r *Response) Close() error {
+	if r.stream == nil {
+		return nil
+	}
+	return r.stream.Close()
+}
+
+// IsStream returns true if the body of the response is streamed and has not been buffered yet.
+func (r *Response) IsStream() bool {
+	return r.stream != nil
+}
+
+// buffer reads the body of a streamed response until EOF, closes it and keeps it in memory.
+func (r *Response) buffer() error {
+	if r.stream == nil {
+		return nil
+	}
+	stream := r.stream
+	r.stream = nil
+	body, err := io.ReadAll(stream)
+	r.body = body
+	if closeErr := stream.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 // Status returns the HTTP status of the response.
 func (r *Response) Status() string {
 	return r.status
 }
 
 // String returns a response body in string format.
+// The body of a streamed response is read until EOF and closed first.
 func (r *Response) String() string {
-	return string(r.body)
+	return string(r.Bytes())
 }
 
 // getContentType returns the content-type of the response. It returns an empty string if the content-type is not set.
 func (r *Response) getContentType() ContentType {
-	if strings.Contains(r.contentType, "application/json") {
+	return parseContentType(r.contentType)
+}
+
+// parseContentType returns the ContentType of the value of a Content-Type header.
+// It returns noneContentType if the value is empty or not one of the supported content types.
+func parseContentType(value string) ContentType {
+	if strings.Contains(value, "application/json") {
 		return jsonContentType
 	}
-	if strings.Contains(r.contentType, "application/xml") {
+	if strings.Contains(value, "application/xml") {
 		return xmlContentType
 	}
-	if strings.Contains(r.contentType, "application/yaml") {
+	if strings.Contains(value, "application/yaml") {
 		return yamlContentType
 	}
-	if strings.Contains(r.contentType, "text/plain") {
+	if strings.Contains(value, "text/plain") {
 		return textContentType
 	}
+	if strings.Contains(value, "application/x-www-form-urlencoded") {
+		return formContentType
+	}
 	return noneContentType
 }
 
@@ -98,7 +213,7 @@ func (r *Response) unmarshalXML(v interface{}) error {
 
 // UnmarshalYAML unmarshal the response body into the given interface.
 func (r *Response) UnmarshalYAML(v interface{}) error {
-	return yaml.Unmarshal(r.body, &v)
+	return yaml.Unmarshal(r.Bytes(), &v)
 }
 
 // unmarshalText unmarshal the response body into the given interface.
@@ -115,7 +230,11 @@ func (r *Response) unmarshalText(v interface{}) error {
 //   - It returns an error if the content-type is not supported.
 //   - It returns an error if the unmarshal method fails.
 //   - It returns an error if the given interface is not a pointer.
+//   - It reads the whole body of a streamed response first.
 func (r *Response) Unmarshal(v interface{}) ErrorContentType {
+	if err := r.buffer(); err != nil {
+		return err
+	}
 	switch r.getContentType() {
 	case jsonContentType:
 		return r.unmarshalJSON(v)
@@ -0,0 +1,281 @@
+package go_requests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// defaultRetryMaxAttempts is the default value for the maximum number of attempts of a request
+	defaultRetryMaxAttempts = 3
+	// defaultRetryInitialBackoff is the default delay before the first retry
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	// defaultRetryMaxBackoff is the default upper bound of the delay between two attempts
+	defaultRetryMaxBackoff = 5 * time.Second
+	// defaultRetryJitter is the default fraction of the delay that is randomized
+	defaultRetryJitter = 0.5
+	// defaultRetryMaxRetryAfter is the default upper bound of the delay asked by a Retry-After header
+	defaultRetryMaxRetryAfter = time.Minute
+)
+
+// ErrorClass classifies transport errors so that a RetryPolicy can decide whether they are worth retrying.
+type ErrorClass int
+
+const (
+	// ErrorClassTimeout matches network timeouts, e.g. dial or response header timeouts.
+	ErrorClassTimeout ErrorClass = 1 << iota
+	// ErrorClassConnection matches connection level failures such as refused or reset connections and unexpected EOFs.
+	ErrorClassConnection
+)
+
+// RetryPolicy is the interface that decides whether and when a failed request is attempted again.
+// A RetryPolicy is attached to a client with Builder.SetRetryPolicy. Without a policy every request is attempted once.
+//
+//	Example:
+//		builder.SetRetryPolicy(go_requests.NewRetryPolicy().
+//			SetMaxAttempts(5).
+//			SetBackoff(200*time.Millisecond, 10*time.Second))
+type RetryPolicy interface {
+	// SetMaxAttempts sets the maximum number of attempts, including the first one.
+	// Values lower than 1 are treated as 1.
+	SetMaxAttempts(attempts int) RetryPolicy
+	// SetBackoff sets the delay before the first retry and the upper bound of the delay.
+	// The delay doubles after every attempt until it reaches max.
+	SetBackoff(initial, max time.Duration) RetryPolicy
+	// SetJitter sets the fraction (0 to 1) of every delay that is randomized to spread retries of concurrent clients.
+	SetJitter(jitter float64) RetryPolicy
+	// SetMaxRetryAfter sets the longest delay asked by a Retry-After header that is waited for.
+	// A response asking for a longer delay is returned without being retried.
+	SetMaxRetryAfter(max time.Duration) RetryPolicy
+	// SetRetryableStatusCodes replaces the HTTP status codes that trigger a retry.
+	SetRetryableStatusCodes(codes ...int) RetryPolicy
+	// SetRetryableErrors replaces the classes of transport errors that trigger a retry.
+	SetRetryableErrors(classes ...ErrorClass) RetryPolicy
+	// SetRetryNonIdempotent allows retrying methods that are not idempotent, such as POST and PATCH.
+	SetRetryNonIdempotent(retry bool) RetryPolicy
+	// GetMaxAttempts returns the maximum number of attempts, including the first one.
+	GetMaxAttempts() int
+	// ShouldRetry reports whether a request with the given method must be attempted again
+	// after it returned the given response or error.
+	ShouldRetry(method string, response *Response, err error) bool
+	// Backoff returns how long to wait before the next attempt. attempt is the number of attempts made so far.
+	Backoff(attempt int, response *Response) time.Duration
+}
+
+// retryPolicyImpl is the default implementation of the RetryPolicy interface
+type retryPolicyImpl struct {
+	maxAttempts        int
+	initialBackoff     time.Duration
+	maxBackoff         time.Duration
+	jitter             float64
+	maxRetryAfter      time.Duration
+	statusCodes        map[int]bool
+	errorClasses       ErrorClass
+	retryNonIdempotent bool
+}
+
+// NewRetryPolicy returns a new RetryPolicy with the default values.
+//
+//   - 3 attempts
+//   - exponential backoff from 100ms up to 5s with 50% jitter
+//   - Retry-After delays up to 1m
+//   - retries on 408, 429, 500, 502, 503 and 504 responses
+//   - retries on timeouts and connection errors
+//   - retries idempotent methods only
+func NewRetryPolicy() RetryPolicy {
+	return &retryPolicyImpl{
+		maxAttempts:    defaultRetryMaxAttempts,
+		initialBackoff: defaultRetryInitialBackoff,
+		maxBackoff:     defaultRetryMaxBackoff,
+		jitter:         defaultRetryJitter,
+		maxRetryAfter:  defaultRetryMaxRetryAfter,
+		statusCodes: map[int]bool{
+			http.StatusRequestTimeout:      true,
+			http.StatusTooManyRequests:     true,
+			http.StatusInternalServerError: true,
+			http.StatusBadGateway:          true,
+			http.StatusServiceUnavailable:  true,
+			http.StatusGatewayTimeout:      true,
+		},
+		errorClasses: ErrorClassTimeout | ErrorClassConnection,
+	}
+}
+
+// SetMaxAttempts sets the maximum number of attempts, including the first one.
+func (r *retryPolicyImpl) SetMaxAttempts(attempts int) RetryPolicy {
+	if attempts < 1 {
+		attempts = 1
+	}
+	r.maxAttempts = attempts
+	return r
+}
+
+// SetBackoff sets the delay before the first retry and the upper bound of the delay.
+func (r *retryPolicyImpl) SetBackoff(initial, max time.Duration) RetryPolicy {
+	if max < initial {
+		max = initial
+	}
+	r.initialBackoff = initial
+	r.maxBackoff = max
+	return r
+}
+
+// SetJitter sets the fraction of every delay that is randomized. It is clamped between 0 and 1.
+func (r *retryPolicyImpl) SetJitter(jitter float64) RetryPolicy {
+	r.jitter = math.Max(0, math.Min(1, jitter))
+	return r
+}
+
+// SetRetryableStatusCodes replaces the HTTP status codes that trigger a retry.
+func (r *retryPolicyImpl) SetRetryableStatusCodes(codes ...int) RetryPolicy {
+	r.statusCodes = make(map[int]bool, len(codes))
+	for _, code := range codes {
+		r.statusCodes[code] = true
+	}
+	return r
+}
+
+// SetRetryableErrors replaces the classes of transport errors that trigger a retry.
+func (r *retryPolicyImpl) SetRetryableErrors(classes ...ErrorClass) RetryPolicy {
+	r.errorClasses = 0
+	for _, class := range classes {
+		r.errorClasses |= class
+	}
+	return r
+}
+
+// SetMaxRetryAfter sets the longest delay asked by a Retry-After header that is waited for.
+func (r *retryPolicyImpl) SetMaxRetryAfter(max time.Duration) RetryPolicy {
+	r.maxRetryAfter = max
+	return r
+}
+
+// SetRetryNonIdempotent allows retrying methods that are not idempotent.
+func (r *retryPolicyImpl) SetRetryNonIdempotent(retry bool) RetryPolicy {
+	r.retryNonIdempotent = retry
+	return r
+}
+
+// GetMaxAttempts returns the maximum number of attempts, including the first one.
+func (r *retryPolicyImpl) GetMaxAttempts() int {
+	return r.maxAttempts
+}
+
+// ShouldRetry reports whether the request must be attempted again.
+// Canceled contexts and expired deadlines are never retried, and neither are the responses whose Retry-After
+// delay is longer than the maximum of the policy.
+func (r *retryPolicyImpl) ShouldRetry(method string, response *Response, err error) bool {
+	if !r.retryNonIdempotent && !isIdempotent(method) {
+		return false
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return classifyError(err)&r.errorClasses != 0
+	}
+	if response == nil || !r.statusCodes[response.StatusCode()] {
+		return false
+	}
+	wait, ok := retryAfter(response)
+	return !ok || wait <= r.maxRetryAfter
+}
+
+// Backoff returns how long to wait before the next attempt.
+// A Retry-After header on a 429 or 503 response takes precedence over the exponential backoff,
+// up to the maximum Retry-After delay of the policy.
+func (r *retryPolicyImpl) Backoff(attempt int, response *Response) time.Duration {
+	if wait, ok := retryAfter(response); ok {
+		if wait > r.maxRetryAfter {
+			wait = r.maxRetryAfter
+		}
+		return wait
+	}
+	backoff := float64(r.initialBackoff) * math.Pow(2, float64(attempt-1))
+	if backoff > float64(r.maxBackoff) {
+		backoff = float64(r.maxBackoff)
+	}
+	if r.jitter > 0 {
+		backoff -= backoff * r.jitter * rand.Float64()
+	}
+	return time.Duration(backoff)
+}
+
+// retryAfter returns the delay asked by the Retry-After header of a 429 or 503 response, if any.
+func retryAfter(response *Response) (time.Duration, bool) {
+	if response == nil || (response.StatusCode() != http.StatusTooManyRequests &&
+		response.StatusCode() != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	return parseRetryAfter(response.Header().Get("Retry-After"))
+}
+
+// isIdempotent reports whether the method is idempotent as defined by RFC 9110 section 9.2.2.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// classifyError returns the ErrorClass of a transport error, or zero if it does not belong to any class.
+func classifyError(err error) ErrorClass {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorClassTimeout
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return ErrorClassConnection
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrorClassConnection
+	}
+	return 0
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either in delay-seconds or as an HTTP-date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,357 @@
+package go_requests
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func Test_goHTTPClient_doWithRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		failures     int32
+		status       int
+		policy       RetryPolicy
+		wantStatus   int
+		wantAttempts int
+	}{
+		{
+			name:         "no policy",
+			method:       http.MethodPut,
+			failures:     1,
+			status:       http.StatusServiceUnavailable,
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retried until success",
+			method:       http.MethodPut,
+			failures:     2,
+			status:       http.StatusServiceUnavailable,
+			policy:       NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond),
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+		},
+		{
+			name:         "max attempts reached",
+			method:       http.MethodPut,
+			failures:     5,
+			status:       http.StatusBadGateway,
+			policy:       NewRetryPolicy().SetMaxAttempts(2).SetBackoff(time.Millisecond, time.Millisecond),
+			wantStatus:   http.StatusBadGateway,
+			wantAttempts: 2,
+		},
+		{
+			name:         "non idempotent method",
+			method:       http.MethodPost,
+			failures:     1,
+			status:       http.StatusServiceUnavailable,
+			policy:       NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond),
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		{
+			name:     "non idempotent method allowed",
+			method:   http.MethodPost,
+			failures: 1,
+			status:   http.StatusServiceUnavailable,
+			policy: NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond).
+				SetRetryNonIdempotent(true),
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name:         "status not retryable",
+			method:       http.MethodPut,
+			failures:     1,
+			status:       http.StatusNotFound,
+			policy:       NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond),
+			wantStatus:   http.StatusNotFound,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != "payload" {
+					t.Errorf("attempt %d got body %q, want %q", atomic.LoadInt32(&calls)+1, body, "payload")
+				}
+				if atomic.AddInt32(&calls, 1) <= tt.failures {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			builder := NewBuilder()
+			builder.SetRetryPolicy(tt.policy)
+			client := builder.Build()
+			var res *Response
+			var err error
+			if tt.method == http.MethodPost {
+				res, err = client.Post(server.URL, []byte("payload"))
+			} else {
+				res, err = client.Put(server.URL, []byte("payload"))
+			}
+			if err != nil {
+				t.Fatalf("doWithRetry() error = %v", err)
+			}
+			if res.StatusCode() != tt.wantStatus {
+				t.Errorf("doWithRetry() status = %v, want %v", res.StatusCode(), tt.wantStatus)
+			}
+			if res.Attempts() != tt.wantAttempts {
+				t.Errorf("doWithRetry() attempts = %v, want %v", res.Attempts(), tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func Test_goHTTPClient_doWithRetry_middlewareHeaders(t *testing.T) {
+	var calls int32
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, strings.Join(r.Header.Values("X-Count"), ","))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	builder := NewBuilder()
+	builder.SetRetryPolicy(NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond))
+	builder.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			req.Header.Add("X-Count", "1")
+			return next(req)
+		}
+	})
+	res, err := builder.Build().Put(server.URL, []byte("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Attempts() != 2 {
+		t.Fatalf("attempts = %d, want 2", res.Attempts())
+	}
+	for i, header := range got {
+		if header != "1" {
+			t.Errorf("attempt %d got X-Count %q, want %q", i+1, header, "1")
+		}
+	}
+}
+
+func Test_goHTTPClient_doWithRetry_retryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	builder := NewBuilder()
+	builder.SetRetryPolicy(NewRetryPolicy())
+	start := time.Now()
+	res, err := builder.Build().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != http.StatusServiceUnavailable || res.Attempts() != 1 {
+		t.Errorf("response = %d after %d attempts, want 503 after 1", res.StatusCode(), res.Attempts())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request took %v, want the response returned without waiting", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("server calls = %d, want 1", got)
+	}
+}
+
+func Test_retryPolicyImpl_ShouldRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		method   string
+		response *Response
+		err      error
+		want     bool
+	}{
+		{
+			name:   "connection refused",
+			policy: NewRetryPolicy(),
+			method: http.MethodGet,
+			err:    syscall.ECONNREFUSED,
+			want:   true,
+		},
+		{
+			name:   "connection errors disabled",
+			policy: NewRetryPolicy().SetRetryableErrors(ErrorClassTimeout),
+			method: http.MethodGet,
+			err:    syscall.ECONNREFUSED,
+			want:   false,
+		},
+		{
+			name:   "unknown error",
+			policy: NewRetryPolicy(),
+			method: http.MethodGet,
+			err:    errors.New("boom"),
+			want:   false,
+		},
+		{
+			name:     "custom status code",
+			policy:   NewRetryPolicy().SetRetryableStatusCodes(http.StatusConflict),
+			method:   http.MethodGet,
+			response: &Response{statusCode: http.StatusConflict},
+			want:     true,
+		},
+		{
+			name:     "retry after within maximum",
+			policy:   NewRetryPolicy(),
+			method:   http.MethodGet,
+			response: &Response{statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"30"}}},
+			want:     true,
+		},
+		{
+			name:     "retry after above maximum",
+			policy:   NewRetryPolicy(),
+			method:   http.MethodGet,
+			response: &Response{statusCode: http.StatusServiceUnavailable, header: http.Header{"Retry-After": {"86400"}}},
+			want:     false,
+		},
+		{
+			name:   "retry after date above maximum",
+			policy: NewRetryPolicy().SetMaxRetryAfter(time.Second),
+			method: http.MethodGet,
+			response: &Response{statusCode: http.StatusTooManyRequests,
+				header: http.Header{"Retry-After": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}}},
+			want: false,
+		},
+		{
+			name:     "success",
+			policy:   NewRetryPolicy(),
+			method:   http.MethodGet,
+			response: &Response{statusCode: http.StatusOK},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.ShouldRetry(tt.method, tt.response, tt.err); got != tt.want {
+				t.Errorf("ShouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_retryPolicyImpl_Backoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		response *Response
+		min      time.Duration
+		max      time.Duration
+	}{
+		{
+			name:    "first attempt",
+			policy:  NewRetryPolicy().SetBackoff(100*time.Millisecond, time.Second).SetJitter(0),
+			attempt: 1,
+			min:     100 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "exponential",
+			policy:  NewRetryPolicy().SetBackoff(100*time.Millisecond, time.Second).SetJitter(0),
+			attempt: 3,
+			min:     400 * time.Millisecond,
+			max:     400 * time.Millisecond,
+		},
+		{
+			name:    "capped",
+			policy:  NewRetryPolicy().SetBackoff(100*time.Millisecond, time.Second).SetJitter(0),
+			attempt: 10,
+			min:     time.Second,
+			max:     time.Second,
+		},
+		{
+			name:    "jitter",
+			policy:  NewRetryPolicy().SetBackoff(100*time.Millisecond, time.Second).SetJitter(0.5),
+			attempt: 1,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "retry after seconds",
+			policy:  NewRetryPolicy().SetBackoff(100*time.Millisecond, time.Second),
+			attempt: 1,
+			response: &Response{
+				statusCode: http.StatusTooManyRequests,
+				header:     http.Header{"Retry-After": []string{"3"}},
+			},
+			min: 3 * time.Second,
+			max: 3 * time.Second,
+		},
+		{
+			name:    "retry after capped",
+			policy:  NewRetryPolicy().SetMaxRetryAfter(2 * time.Second),
+			attempt: 1,
+			response: &Response{
+				statusCode: http.StatusServiceUnavailable,
+				header:     http.Header{"Retry-After": []string{"86400"}},
+			},
+			min: 2 * time.Second,
+			max: 2 * time.Second,
+		},
+		{
+			name:    "retry after ignored on other status",
+			policy:  NewRetryPolicy().SetBackoff(100*time.Millisecond, time.Second).SetJitter(0),
+			attempt: 1,
+			response: &Response{
+				statusCode: http.StatusBadGateway,
+				header:     http.Header{"Retry-After": []string{"3"}},
+			},
+			min: 100 * time.Millisecond,
+			max: 100 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Backoff(tt.attempt, tt.response)
+			if got < tt.min || got > tt.max {
+				t.Errorf("Backoff() = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{name: "empty", value: "", want: 0, wantOk: false},
+		{name: "seconds", value: "120", want: 2 * time.Minute, wantOk: true},
+		{name: "negative", value: "-1", want: 0, wantOk: false},
+		{name: "past date", value: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOk: true},
+		{name: "invalid", value: "soon", want: 0, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("parseRetryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package go_requests
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// sigV4Algorithm is the signing algorithm of AWS Signature Version 4
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	// sigV4TimeFormat is the format of the X-Amz-Date header
+	sigV4TimeFormat = "20060102T150405Z"
+	// sigV4DateFormat is the format of the date of the credential scope
+	sigV4DateFormat = "20060102"
+	// sigV4UnsignedPayload is the payload hash used when the body cannot be read twice
+	sigV4UnsignedPayload = "UNSIGNED-PAYLOAD"
+	// sigV4MaxPresignExpiry is the longest validity of a presigned URL accepted by AWS
+	sigV4MaxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// sigV4IgnoredHeaders are the headers that are never signed because proxies and the transport may change them.
+var sigV4IgnoredHeaders = map[string]bool{
+	"Authorization":   true,
+	"User-Agent":      true,
+	"X-Amzn-Trace-Id": true,
+	"Expect":          true,
+	"Connection":      true,
+	"Accept-Encoding": true,
+}
+
+// SigV4Signer is the interface that signs requests with AWS Signature Version 4.
+// It is attached to a client with Builder.SetSigV4Signer, in which case every request is signed right before it is sent.
+type SigV4Signer interface {
+	// Sign adds the X-Amz-Date, X-Amz-Security-Token and Authorization headers to the request.
+	// The payload is hashed from req.GetBody. Requests whose body cannot be read twice are signed with UNSIGNED-PAYLOAD,
+	// as are requests that already carry a X-Amz-Content-Sha256 header with that value.
+	Sign(req *http.Request) error
+	// Presign returns a URL that grants access to the resource with the given method until expires elapses.
+	// It is mostly used for GET and PUT requests to S3 compatible object stores.
+	Presign(method Method, url string, expires time.Duration) (string, error)
+}
+
+// sigV4SignerImpl is the implementation of the SigV4Signer interface
+type sigV4SignerImpl struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	region       string
+	service      string
+}
+
+// NewSigV4Signer returns a new SigV4Signer for the given credentials, region and service.
+// sessionToken is only needed for temporary credentials and may be empty.
+//
+//	Example:
+//		signer := go_requests.NewSigV4Signer("minioadmin", "minioadmin", "", "us-east-1", "s3")
+//		builder.SetSigV4Signer(signer)
+//		url, err := signer.Presign(http.MethodGet, "http://localhost:9000/bucket/object.txt", 15*time.Minute)
+func NewSigV4Signer(accessKey, secretKey, sessionToken, region, service string) SigV4Signer {
+	return &sigV4SignerImpl{
+		accessKey:    accessKey,
+		secretKey:    secretKey,
+		sessionToken: sessionToken,
+		region:       region,
+		service:      service,
+	}
+}
+
+// Sign signs the request with the current time.
+func (s *sigV4SignerImpl) Sign(req *http.Request) error {
+	return s.sign(req, time.Now())
+}
+
+// sign signs the request as if it were sent at t.
+func (s *sigV4SignerImpl) sign(req *http.Request, t time.Time) error {
+	t = t.UTC()
+	payloadHash, err := s.payloadHash(req)
+	if err != nil {
+		return err
+	}
+	req.Header.Del("Authorization")
+	req.Header.Set("X-Amz-Date", t.Format(sigV4TimeFormat))
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	if s.service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+	s.normalizeURL(req.URL)
+
+	signedHeaders, canonicalHeaders := s.canonicalHeaders(req)
+	scope := s.scope(t)
+	signature := s.signature(t, scope, s.canonicalRequest(req.Method, req.URL, canonicalHeaders, signedHeaders, payloadHash))
+	req.Header.Set("Authorization", sigV4Algorithm+" Credential="+s.accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+	return nil
+}
+
+// Presign returns a presigned URL that is valid for expires, starting now.
+func (s *sigV4SignerImpl) Presign(method Method, rawURL string, expires time.Duration) (string, error) {
+	return s.presign(method, rawURL, expires, time.Now())
+}
+
+// presign returns a presigned URL that is valid for expires, starting at t.
+func (s *sigV4SignerImpl) presign(method Method, rawURL string, expires time.Duration, t time.Time) (string, error) {
+	if expires <= 0 || expires > sigV4MaxPresignExpiry {
+		return "", errors.New("sigv4: presigned URL expiry must be between 1 second and 7 days")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	t = t.UTC()
+	scope := s.scope(t)
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", t.Format(sigV4TimeFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if s.sessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	u.RawQuery = query.Encode()
+	s.normalizeURL(u)
+
+	canonicalHeaders := "host:" + u.Host + "\n"
+	signature := s.signature(t, scope, s.canonicalRequest(string(method), u, canonicalHeaders, "host", sigV4UnsignedPayload))
+	u.RawQuery += "&X-Amz-Signature=" + signature
+	return u.String(), nil
+}
+
+// payloadHash returns the hex encoded SHA-256 of the request body.
+func (s *sigV4SignerImpl) payloadHash(req *http.Request) (string, error) {
+	if hash := req.Header.Get("X-Amz-Content-Sha256"); hash != "" {
+		return hash, nil
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return sha256Hex(nil), nil
+	}
+	if req.GetBody == nil {
+		return sigV4UnsignedPayload, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(body)
+	h := sha256.New()
+	if _, err = io.Copy(h, body); err != nil {
+		return "", err
+	}
+	// bodies backed by an io.Seeker share their offset with the body being sent, so rewind it
+	_ = req.Body.Close()
+	if req.Body, err = req.GetBody(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// normalizeURL rewrites the escaped path and the query of the URL so that what is sent matches the canonical request.
+func (s *sigV4SignerImpl) normalizeURL(u *url.URL) {
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	if escaped := sigV4Escape(path, false); escaped != path {
+		u.RawPath = escaped
+	}
+	u.RawQuery = s.canonicalQuery(u)
+}
+
+// canonicalRequest returns the canonical request as defined by Signature Version 4.
+func (s *sigV4SignerImpl) canonicalRequest(method string, u *url.URL, canonicalHeaders, signedHeaders, payloadHash string) string {
+	uri := u.EscapedPath()
+	if uri == "" {
+		uri = "/"
+	}
+	// every service but S3 expects the path to be encoded twice
+	if s.service != "s3" {
+		uri = sigV4Escape(uri, false)
+	}
+	return strings.Join([]string{method, uri, u.RawQuery, canonicalHeaders, signedHeaders, payloadHash}, "\n")
+}
+
+// canonicalQuery returns the query sorted by key and value and escaped as RFC 3986 requires.
+func (s *sigV4SignerImpl) canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, sigV4Escape(key, true)+"="+sigV4Escape(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// canonicalHeaders returns the semicolon separated list of the signed headers and their canonical form.
+func (s *sigV4SignerImpl) canonicalHeaders(req *http.Request) (string, string) {
+	values := map[string]string{"host": req.Host}
+	if req.Host == "" {
+		values["host"] = req.URL.Host
+	}
+	for key, headerValues := range req.Header {
+		if sigV4IgnoredHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		trimmed := make([]string, len(headerValues))
+		for i, value := range headerValues {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		values[strings.ToLower(key)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + values[name] + "\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+// scope returns the credential scope of the signature.
+func (s *sigV4SignerImpl) scope(t time.Time) string {
+	return t.Format(sigV4DateFormat) + "/" + s.region + "/" + s.service + "/aws4_request"
+}
+
+// signature returns the hex encoded signature of the canonical request.
+func (s *sigV4SignerImpl) signature(t time.Time, scope, canonicalRequest string) string {
+	stringToSign := sigV4Algorithm + "\n" + t.Format(sigV4TimeFormat) + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), t.Format(sigV4DateFormat))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s.service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// sigV4Middleware signs every request with the signer right before it is sent.
+func sigV4Middleware(signer SigV4Signer) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*Response, error) {
+			if err := signer.Sign(req); err != nil {
+				return nil, err
+			}
+			return next(req)
+		}
+	}
+}
+
+// sigV4Escape escapes every byte but the unreserved characters of RFC 3986, and the slashes unless encodeSlash is true.
+func sigV4Escape(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&15])
+	}
+	return b.String()
+}
+
+// sha256Hex returns the hex encoded SHA-256 of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with the given key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,67 @@
+package go_requests
+
+import (
+	"net/http"
+)
+
+// GetAs sends a GET request with the client and decodes the response body into a T,
+// using the Content-Type of the response as Response.Unmarshal does.
+//
+// It returns a *StatusError if the status code of the response is not 2xx, and the zero value of T if the response
+// has no body, e.g. 204 No Content. The response is always returned with the error, unless the request could not
+// be sent.
+//
+//	Example:
+//		pets, response, err := go_requests.GetAs[[]Pet](client, "https://petstore.swagger.io/v2/pet/findByStatus")
+func GetAs[T any](client Client, url string, headers ...http.Header) (T, *Response, error) {
+	return decodeAs[T](client.Get(url, headers...))
+}
+
+// PostAs sends a POST request whose body is v encoded as Client.PostValue does, and decodes the response body into a T.
+// See GetAs.
+//
+//	Example:
+//		order, response, err := go_requests.PostAs[Order](client, "https://petstore.swagger.io/v2/store/order", order)
+func PostAs[T any](client Client, url string, v interface{}, headers ...http.Header) (T, *Response, error) {
+	return decodeAs[T](client.PostValue(url, v, headers...))
+}
+
+// PutAs sends a PUT request whose body is v encoded as Client.PutValue does, and decodes the response body into a T.
+// See GetAs.
+func PutAs[T any](client Client, url string, v interface{}, headers ...http.Header) (T, *Response, error) {
+	return decodeAs[T](client.PutValue(url, v, headers...))
+}
+
+// PatchAs sends a PATCH request whose body is v encoded as Client.PatchValue does, and decodes the response body into a T.
+// See GetAs.
+func PatchAs[T any](client Client, url string, v interface{}, headers ...http.Header) (T, *Response, error) {
+	return decodeAs[T](client.PatchValue(url, v, headers...))
+}
+
+// DeleteAs sends a DELETE request without body and decodes the response body into a T.
+// See GetAs.
+func DeleteAs[T any](client Client, url string, headers ...http.Header) (T, *Response, error) {
+	return decodeAs[T](client.Delete(url, nil, headers...))
+}
+
+// decodeAs checks the status of the response and decodes its body into a T.
+func decodeAs[T any](response *Response, err error) (T, *Response, error) {
+	var result T
+	if err != nil {
+		return result, response, err
+	}
+	if response.StatusCode() < http.StatusOK || response.StatusCode() >= http.StatusMultipleChoices {
+		return result, response, &StatusError{
+			StatusCode: response.StatusCode(),
+			Status:     response.Status(),
+			Body:       response.Bytes(),
+		}
+	}
+	if len(response.Bytes()) == 0 {
+		return result, response, nil
+	}
+	if err = response.Unmarshal(&result); err != nil {
+		return result, response, err
+	}
+	return result, response, nil
+}
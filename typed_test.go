@@ -0,0 +1,95 @@
+package go_requests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_GetAs(t *testing.T) {
+	type pet struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pets":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"id":1,"name":"doggie"}]`))
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/missing":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		default:
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte("raw"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewBuilder().Build()
+	tests := []struct {
+		name       string
+		path       string
+		want       []pet
+		wantStatus int
+		wantErr    bool
+	}{
+		{name: "decoded", path: "/pets", want: []pet{{ID: 1, Name: "doggie"}}},
+		{name: "no content", path: "/empty"},
+		{name: "status error", path: "/missing", wantStatus: http.StatusNotFound, wantErr: true},
+		{name: "unsupported content type", path: "/raw", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, res, err := GetAs[[]pet](client, server.URL+tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetAs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if res == nil {
+				t.Fatalf("GetAs() response = nil")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAs() = %v, want %v", got, tt.want)
+			}
+			var statusErr *StatusError
+			if errors.As(err, &statusErr) != (tt.wantStatus != 0) {
+				t.Fatalf("GetAs() error = %v, want a StatusError %v", err, tt.wantStatus != 0)
+			}
+			if statusErr != nil && (statusErr.StatusCode != tt.wantStatus || string(statusErr.Body) != `{"message":"not found"}`) {
+				t.Errorf("GetAs() StatusError = %+v", statusErr)
+			}
+		})
+	}
+}
+
+func Test_PostAs(t *testing.T) {
+	type order struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var received order
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received.Status = "placed"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(received)
+	}))
+	defer server.Close()
+
+	got, res, err := PostAs[order](NewBuilder().Build(), server.URL, order{ID: 7})
+	if err != nil {
+		t.Fatalf("PostAs() error = %v", err)
+	}
+	if res.StatusCode() != http.StatusOK || got != (order{ID: 7, Status: "placed"}) {
+		t.Errorf("PostAs() = %+v, %v", got, res.StatusCode())
+	}
+}
@@ -0,0 +1,394 @@
+package go_requests
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxURITemplatePrefix is the largest prefix modifier allowed by RFC 6570, e.g. {var:9999}
+const maxURITemplatePrefix = 9999
+
+// URITemplate is an RFC 6570 URI template, e.g. "https://api.example.com/users{/id}{?page,size}".
+// All the levels of the RFC are supported, up to level 4 with prefix modifiers and exploded variables.
+//
+// The values used to expand a template are strings, lists ([]string or any other slice) and associative arrays
+// (map[string]string or any other map, whose keys are expanded in sorted order, or [][2]string key/value pairs that
+// keep their order). Other values, such as numbers, are formatted with fmt. Missing and nil values, as well as
+// empty lists and maps, are undefined and expand to nothing.
+type URITemplate interface {
+	// Expand returns the URI of the template with the given values.
+	Expand(values map[string]interface{}) (string, error)
+	// Variables returns the names of the variables of the template, in order of appearance.
+	Variables() []string
+	// String returns the template.
+	String() string
+}
+
+// uriTemplateOperator describes how the expressions of an operator are expanded (RFC 6570 appendix A)
+type uriTemplateOperator struct {
+	first         string
+	separator     string
+	named         bool
+	ifEmpty       string
+	allowReserved bool
+}
+
+// uriTemplateOperators are the operators of RFC 6570 by their character, the simple string expansion has none
+var uriTemplateOperators = map[byte]uriTemplateOperator{
+	0:   {first: "", separator: ","},
+	'+': {first: "", separator: ",", allowReserved: true},
+	'.': {first: ".", separator: "."},
+	'/': {first: "/", separator: "/"},
+	';': {first: ";", separator: ";", named: true},
+	'?': {first: "?", separator: "&", named: true, ifEmpty: "="},
+	'&': {first: "&", separator: "&", named: true, ifEmpty: "="},
+	'#': {first: "#", separator: ",", allowReserved: true},
+}
+
+// uriTemplateVariable is a variable of an expression, e.g. list* or var:3
+type uriTemplateVariable struct {
+	name    string
+	prefix  int
+	explode bool
+}
+
+// uriTemplatePart is either a literal or an expression of a template
+type uriTemplatePart struct {
+	literal   string
+	operator  uriTemplateOperator
+	variables []uriTemplateVariable
+}
+
+// uriTemplateImpl is the implementation of the URITemplate interface
+type uriTemplateImpl struct {
+	template string
+	parts    []uriTemplatePart
+}
+
+// NewURITemplate parses an RFC 6570 URI template.
+// It returns an error if an expression is not closed, uses a reserved operator or has an invalid variable.
+//
+//	Example:
+//		template, err := go_requests.NewURITemplate("/pets{/id}{?tags*,limit}")
+//		path, err := template.Expand(map[string]interface{}{
+//			"id":   42,
+//			"tags": []string{"dogs", "cats"},
+//		})
+//		// path is "/pets/42?tags=dogs&tags=cats"
+func NewURITemplate(template string) (URITemplate, error) {
+	t := &uriTemplateImpl{template: template}
+	rest := template
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			t.parts = append(t.parts, uriTemplatePart{literal: rest})
+			break
+		}
+		if start > 0 {
+			t.parts = append(t.parts, uriTemplatePart{literal: rest[:start]})
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("uri template %q: unclosed expression", template)
+		}
+		part, err := parseURITemplateExpression(rest[start+1 : start+end])
+		if err != nil {
+			return nil, fmt.Errorf("uri template %q: %w", template, err)
+		}
+		t.parts = append(t.parts, part)
+		rest = rest[start+end+1:]
+	}
+	return t, nil
+}
+
+// ExpandURITemplate parses the RFC 6570 URI template and expands it with the given values.
+//
+//	Example:
+//		url, err := go_requests.ExpandURITemplate("{+base}/search{?q,page}", map[string]interface{}{
+//			"base": "https://api.example.com",
+//			"q":    "go requests",
+//		})
+//		// url is "https://api.example.com/search?q=go%20requests"
+func ExpandURITemplate(template string, values map[string]interface{}) (string, error) {
+	t, err := NewURITemplate(template)
+	if err != nil {
+		return "", err
+	}
+	return t.Expand(values)
+}
+
+// parseURITemplateExpression parses the content of an expression, e.g. "?page,size" of "{?page,size}".
+func parseURITemplateExpression(expression string) (uriTemplatePart, error) {
+	var operatorChar byte
+	if expression != "" {
+		switch expression[0] {
+		case '+', '.', '/', ';', '?', '&', '#':
+			operatorChar = expression[0]
+			expression = expression[1:]
+		case '=', ',', '!', '@', '|':
+			return uriTemplatePart{}, fmt.Errorf("reserved operator %q", expression[0])
+		}
+	}
+	part := uriTemplatePart{operator: uriTemplateOperators[operatorChar]}
+	for _, spec := range strings.Split(expression, ",") {
+		variable := uriTemplateVariable{name: spec}
+		if strings.HasSuffix(spec, "*") {
+			variable.name, variable.explode = strings.TrimSuffix(spec, "*"), true
+		} else if name, prefix, ok := strings.Cut(spec, ":"); ok {
+			length, err := strconv.Atoi(prefix)
+			if err != nil || length <= 0 || length > maxURITemplatePrefix || prefix[0] == '0' {
+				return uriTemplatePart{}, fmt.Errorf("invalid prefix modifier %q", spec)
+			}
+			variable.name, variable.prefix = name, length
+		}
+		if !validURITemplateVariable(variable.name) {
+			return uriTemplatePart{}, fmt.Errorf("invalid variable name %q", variable.name)
+		}
+		part.variables = append(part.variables, variable)
+	}
+	return part, nil
+}
+
+// validURITemplateVariable returns true if name is a varname of RFC 6570: letters, digits, underscores,
+// percent-encoded triplets and dots between them.
+func validURITemplateVariable(name string) bool {
+	if name == "" || name[0] == '.' || name[len(name)-1] == '.' || strings.Contains(name, "..") {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case isAlphaNum(c) || c == '_' || c == '.':
+		case c == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Expand returns the URI of the template with the given values.
+func (t *uriTemplateImpl) Expand(values map[string]interface{}) (string, error) {
+	var b strings.Builder
+	for _, part := range t.parts {
+		if part.variables == nil {
+			b.WriteString(uriTemplateEncode(part.literal, true))
+			continue
+		}
+		if err := part.expand(&b, values); err != nil {
+			return "", fmt.Errorf("uri template %q: %w", t.template, err)
+		}
+	}
+	return b.String(), nil
+}
+
+// Variables returns the names of the variables of the template.
+func (t *uriTemplateImpl) Variables() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, part := range t.parts {
+		for _, variable := range part.variables {
+			if !seen[variable.name] {
+				seen[variable.name] = true
+				names = append(names, variable.name)
+			}
+		}
+	}
+	return names
+}
+
+// String returns the template.
+func (t *uriTemplateImpl) String() string {
+	return t.template
+}
+
+// expand writes the expansion of the expression to b, as the algorithm of RFC 6570 appendix A does.
+func (p *uriTemplatePart) expand(b *strings.Builder, values map[string]interface{}) error {
+	op := p.operator
+	first := true
+	for _, variable := range p.variables {
+		value, defined, err := uriTemplateValue(values[variable.name])
+		if err != nil {
+			return fmt.Errorf("variable %q: %w", variable.name, err)
+		}
+		if !defined {
+			continue
+		}
+		if first {
+			b.WriteString(op.first)
+			first = false
+		} else {
+			b.WriteString(op.separator)
+		}
+		switch value := value.(type) {
+		case string:
+			if variable.prefix > 0 {
+				value = truncateRunes(value, variable.prefix)
+			}
+			writeURITemplateName(b, op, variable.name, value == "")
+			b.WriteString(uriTemplateEncode(value, op.allowReserved))
+		case []string:
+			if variable.prefix > 0 {
+				return fmt.Errorf("variable %q: prefix modifier applied to a list", variable.name)
+			}
+			if !variable.explode {
+				writeURITemplateName(b, op, variable.name, false)
+				for i, item := range value {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					b.WriteString(uriTemplateEncode(item, op.allowReserved))
+				}
+				continue
+			}
+			for i, item := range value {
+				if i > 0 {
+					b.WriteString(op.separator)
+				}
+				if op.named {
+					writeURITemplateName(b, op, variable.name, item == "")
+				}
+				b.WriteString(uriTemplateEncode(item, op.allowReserved))
+			}
+		case [][2]string:
+			if variable.prefix > 0 {
+				return fmt.Errorf("variable %q: prefix modifier applied to an associative array", variable.name)
+			}
+			if !variable.explode {
+				writeURITemplateName(b, op, variable.name, false)
+				for i, pair := range value {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					b.WriteString(uriTemplateEncode(pair[0], op.allowReserved) + "," + uriTemplateEncode(pair[1], op.allowReserved))
+				}
+				continue
+			}
+			for i, pair := range value {
+				if i > 0 {
+					b.WriteString(op.separator)
+				}
+				b.WriteString(uriTemplateEncode(pair[0], op.allowReserved))
+				if pair[1] == "" {
+					if op.named {
+						b.WriteString(op.ifEmpty)
+					} else {
+						b.WriteByte('=')
+					}
+					continue
+				}
+				b.WriteByte('=')
+				b.WriteString(uriTemplateEncode(pair[1], op.allowReserved))
+			}
+		}
+	}
+	return nil
+}
+
+// writeURITemplateName writes the name of a variable of a named operator, followed by "=" or, if the value is empty,
+// the ifEmpty string of the operator.
+func writeURITemplateName(b *strings.Builder, op uriTemplateOperator, name string, empty bool) {
+	if !op.named {
+		return
+	}
+	b.WriteString(name)
+	if empty {
+		b.WriteString(op.ifEmpty)
+		return
+	}
+	b.WriteByte('=')
+}
+
+// uriTemplateValue normalizes a value to a string, a []string or a [][2]string of sorted key/value pairs.
+// It returns false if the value is undefined.
+func uriTemplateValue(value interface{}) (interface{}, bool, error) {
+	switch value := value.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		return value, true, nil
+	case []string:
+		return value, len(value) > 0, nil
+	case [][2]string:
+		return value, len(value) > 0, nil
+	case fmt.Stringer:
+		return value.String(), true, nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, false, nil
+		}
+		return uriTemplateValue(v.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return items, len(items) > 0, nil
+	case reflect.Map:
+		pairs := make([][2]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			pairs = append(pairs, [2]string{fmt.Sprint(key.Interface()), fmt.Sprint(v.MapIndex(key).Interface())})
+		}
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+		return pairs, len(pairs) > 0, nil
+	case reflect.Struct, reflect.Func, reflect.Chan:
+		return nil, false, fmt.Errorf("unsupported value of type %T", value)
+	}
+	return fmt.Sprint(value), true, nil
+}
+
+// uriTemplateEncode percent-encodes every character of s but the unreserved ones and, if allowReserved is true,
+// the reserved ones and the percent-encoded triplets.
+func uriTemplateEncode(s string, allowReserved bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isAlphaNum(c) || c == '-' || c == '.' || c == '_' || c == '~':
+			b.WriteByte(c)
+		case allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0:
+			b.WriteByte(c)
+		case allowReserved && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteString(s[i : i+3])
+			i += 2
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&15])
+		}
+	}
+	return b.String()
+}
+
+// truncateRunes returns the first n characters of s.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	i := 0
+	for n > 0 {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n--
+	}
+	return s[:i]
+}
+
+// isAlphaNum returns true if c is an ASCII letter or digit.
+func isAlphaNum(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// isHex returns true if c is a hexadecimal digit.
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
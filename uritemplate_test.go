@@ -0,0 +1,229 @@
+package go_requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// rfc6570Values are the variables of the examples of RFC 6570 section 3.2
+var rfc6570Values = map[string]interface{}{
+	"count":      []string{"one", "two", "three"},
+	"dom":        []string{"example", "com"},
+	"dub":        "me/too",
+	"hello":      "Hello World!",
+	"half":       "50%",
+	"var":        "value",
+	"who":        "fred",
+	"base":       "http://example.com/home/",
+	"path":       "/foo/bar",
+	"list":       []string{"red", "green", "blue"},
+	"keys":       [][2]string{{"semi", ";"}, {"dot", "."}, {"comma", ","}},
+	"v":          "6",
+	"x":          "1024",
+	"y":          "768",
+	"empty":      "",
+	"empty_keys": map[string]string{},
+	"undef":      nil,
+}
+
+func TestExpandURITemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		// simple string expansion
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+		{"{half}", "50%25"},
+		{"O{empty}X", "OX"},
+		{"O{undef}X", "OX"},
+		{"{x,y}", "1024,768"},
+		{"{x,hello,y}", "1024,Hello%20World%21,768"},
+		{"?{x,empty}", "?1024,"},
+		{"?{x,undef}", "?1024"},
+		{"?{undef,y}", "?768"},
+		{"{var:3}", "val"},
+		{"{var:30}", "value"},
+		{"{list}", "red,green,blue"},
+		{"{list*}", "red,green,blue"},
+		{"{keys}", "semi,%3B,dot,.,comma,%2C"},
+		{"{keys*}", "semi=%3B,dot=.,comma=%2C"},
+		// reserved expansion
+		{"{+var}", "value"},
+		{"{+hello}", "Hello%20World!"},
+		{"{+half}", "50%25"},
+		{"{base}index", "http%3A%2F%2Fexample.com%2Fhome%2Findex"},
+		{"{+base}index", "http://example.com/home/index"},
+		{"O{+empty}X", "OX"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"here?ref={+path}", "here?ref=/foo/bar"},
+		{"up{+path}{var}/here", "up/foo/barvalue/here"},
+		{"{+x,hello,y}", "1024,Hello%20World!,768"},
+		{"{+path:6}/here", "/foo/b/here"},
+		{"{+list*}", "red,green,blue"},
+		{"{+keys}", "semi,;,dot,.,comma,,"},
+		{"{+keys*}", "semi=;,dot=.,comma=,"},
+		// fragment expansion
+		{"{#var}", "#value"},
+		{"{#hello}", "#Hello%20World!"},
+		{"{#half}", "#50%25"},
+		{"foo{#empty}", "foo#"},
+		{"foo{#undef}", "foo"},
+		{"{#x,hello,y}", "#1024,Hello%20World!,768"},
+		{"{#path,x}/here", "#/foo/bar,1024/here"},
+		{"{#path:6}/here", "#/foo/b/here"},
+		{"{#list}", "#red,green,blue"},
+		{"{#list*}", "#red,green,blue"},
+		{"{#keys}", "#semi,;,dot,.,comma,,"},
+		{"{#keys*}", "#semi=;,dot=.,comma=,"},
+		// label expansion
+		{"{.who}", ".fred"},
+		{"{.who,who}", ".fred.fred"},
+		{"{.half,who}", ".50%25.fred"},
+		{"www{.dom*}", "www.example.com"},
+		{"X{.var}", "X.value"},
+		{"X{.empty}", "X."},
+		{"X{.undef}", "X"},
+		{"X{.var:3}", "X.val"},
+		{"X{.list}", "X.red,green,blue"},
+		{"X{.list*}", "X.red.green.blue"},
+		{"X{.keys}", "X.semi,%3B,dot,.,comma,%2C"},
+		{"X{.keys*}", "X.semi=%3B.dot=..comma=%2C"},
+		{"X{.empty_keys}", "X"},
+		{"X{.empty_keys*}", "X"},
+		// path segment expansion
+		{"{/who}", "/fred"},
+		{"{/who,who}", "/fred/fred"},
+		{"{/half,who}", "/50%25/fred"},
+		{"{/who,dub}", "/fred/me%2Ftoo"},
+		{"{/var}", "/value"},
+		{"{/var,empty}", "/value/"},
+		{"{/var,undef}", "/value"},
+		{"{/var,x}/here", "/value/1024/here"},
+		{"{/var:1,var}", "/v/value"},
+		{"{/list}", "/red,green,blue"},
+		{"{/list*}", "/red/green/blue"},
+		{"{/list*,path:4}", "/red/green/blue/%2Ffoo"},
+		{"{/keys}", "/semi,%3B,dot,.,comma,%2C"},
+		{"{/keys*}", "/semi=%3B/dot=./comma=%2C"},
+		// path-style parameter expansion
+		{"{;who}", ";who=fred"},
+		{"{;half}", ";half=50%25"},
+		{"{;empty}", ";empty"},
+		{"{;v,empty,who}", ";v=6;empty;who=fred"},
+		{"{;v,bar,who}", ";v=6;who=fred"},
+		{"{;x,y}", ";x=1024;y=768"},
+		{"{;x,y,empty}", ";x=1024;y=768;empty"},
+		{"{;x,y,undef}", ";x=1024;y=768"},
+		{"{;hello:5}", ";hello=Hello"},
+		{"{;list}", ";list=red,green,blue"},
+		{"{;list*}", ";list=red;list=green;list=blue"},
+		{"{;keys}", ";keys=semi,%3B,dot,.,comma,%2C"},
+		{"{;keys*}", ";semi=%3B;dot=.;comma=%2C"},
+		// form-style query expansion
+		{"{?who}", "?who=fred"},
+		{"{?half}", "?half=50%25"},
+		{"{?x,y}", "?x=1024&y=768"},
+		{"{?x,y,empty}", "?x=1024&y=768&empty="},
+		{"{?x,y,undef}", "?x=1024&y=768"},
+		{"{?var:3}", "?var=val"},
+		{"{?list}", "?list=red,green,blue"},
+		{"{?list*}", "?list=red&list=green&list=blue"},
+		{"{?keys}", "?keys=semi,%3B,dot,.,comma,%2C"},
+		{"{?keys*}", "?semi=%3B&dot=.&comma=%2C"},
+		// form-style query continuation
+		{"{&who}", "&who=fred"},
+		{"{&half}", "&half=50%25"},
+		{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"{&x,y,empty}", "&x=1024&y=768&empty="},
+		{"{&var:3}", "&var=val"},
+		{"{&list}", "&list=red,green,blue"},
+		{"{&list*}", "&list=red&list=green&list=blue"},
+		{"{&keys}", "&keys=semi,%3B,dot,.,comma,%2C"},
+		{"{&keys*}", "&semi=%3B&dot=.&comma=%2C"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := ExpandURITemplate(tt.template, rfc6570Values)
+			if err != nil {
+				t.Fatalf("ExpandURITemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandURITemplate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandURITemplate_values(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		values   map[string]interface{}
+		want     string
+	}{
+		{name: "int", template: "/pet/{id}", values: map[string]interface{}{"id": 42}, want: "/pet/42"},
+		{name: "int slice", template: "{?ids*}", values: map[string]interface{}{"ids": []int{1, 2}}, want: "?ids=1&ids=2"},
+		{name: "sorted map", template: "{?filter*}", values: map[string]interface{}{"filter": map[string]string{"b": "2", "a": "1"}}, want: "?a=1&b=2"},
+		{name: "nil pointer", template: "/pet{/id}", values: map[string]interface{}{"id": (*int)(nil)}, want: "/pet"},
+		{name: "unicode prefix", template: "{name:2}", values: map[string]interface{}{"name": "été"}, want: "%C3%A9t"},
+		{name: "literal", template: "/a b/{x}", values: map[string]interface{}{"x": "1"}, want: "/a%20b/1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandURITemplate(tt.template, tt.values)
+			if err != nil {
+				t.Fatalf("ExpandURITemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandURITemplate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewURITemplate_errors(t *testing.T) {
+	for _, template := range []string{"/pet/{id", "{=x}", "{|x}", "{x:0}", "{x:10000}", "{x:a}", "{}", "{a b}", "{.x.}"} {
+		t.Run(template, func(t *testing.T) {
+			if _, err := NewURITemplate(template); err == nil {
+				t.Errorf("NewURITemplate(%q) error = nil, want an error", template)
+			}
+		})
+	}
+	if _, err := ExpandURITemplate("{list:3}", map[string]interface{}{"list": []string{"a"}}); err == nil {
+		t.Error("ExpandURITemplate() error = nil, want an error for a prefix of a list")
+	}
+}
+
+func Test_uriTemplateImpl_Variables(t *testing.T) {
+	template, err := NewURITemplate("{+base}/pets{/id}{?tags*,id,limit:2}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"base", "id", "tags", "limit"}
+	if got := template.Variables(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Variables() = %v, want %v", got, want)
+	}
+}
+
+func Test_requestImpl_SetTemplateValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.RequestURI()))
+	}))
+	defer server.Close()
+	builder := NewBuilder()
+	builder.SetBaseURL(server.URL + "/v2")
+	client := builder.Build()
+	req := NewRequest(http.MethodGet, "/pet{/id}{?tags*}").
+		SetTemplateValues(map[string]interface{}{"id": 7, "tags": []string{"dogs", "cats"}})
+	req.QueryParams().Set("limit", "1")
+	response, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := response.String(), "/v2/pet/7?tags=dogs&tags=cats&limit=1"; got != want {
+		t.Errorf("request URI = %v, want %v", got, want)
+	}
+}
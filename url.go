@@ -0,0 +1,89 @@
+package go_requests
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ExpandPath replaces the {name} parameters of the path of template with the values of params.
+// Every value is escaped as a single path segment, so a value containing a slash does not add a segment and the
+// values "." and ".." are escaped so that they are not dot segments.
+// The query of template, if any, is left untouched. It returns an error if a parameter has no value
+// or a brace is not closed.
+//
+//	Example:
+//		path, err := go_requests.ExpandPath("/pet/{petId}/uploadImage", map[string]string{"petId": "42"})
+func ExpandPath(template string, params map[string]string) (string, error) {
+	path, query, hasQuery := strings.Cut(template, "?")
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			b.WriteString(path)
+			break
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("path template %q: unclosed brace", template)
+		}
+		name := path[start+1 : start+end]
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("path template %q: missing value for parameter %q", template, name)
+		}
+		b.WriteString(path[:start])
+		b.WriteString(escapePathSegment(value))
+		path = path[start+end+1:]
+	}
+	if hasQuery {
+		b.WriteString("?" + query)
+	}
+	return b.String(), nil
+}
+
+// escapePathSegment escapes value as a single segment of a path. The dot segments "." and ".." are escaped too,
+// since url.PathEscape leaves them as is and servers would remove them along with the previous segment.
+func escapePathSegment(value string) string {
+	switch value {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	}
+	return url.PathEscape(value)
+}
+
+// resolveURL returns rawURL resolved against the base URL.
+// Unlike url.URL.ResolveReference, the path of rawURL is always appended to the path of base,
+// so that "https://example.com/v2" and "pet" give "https://example.com/v2/pet".
+func resolveURL(base, rawURL string) (string, error) {
+	if base == "" {
+		return rawURL, nil
+	}
+	ref, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if ref.IsAbs() || ref.Host != "" {
+		return rawURL, nil
+	}
+	resolved, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	if ref.Path != "" {
+		// the escaped paths are joined too, so that escaped slashes of the segments are kept
+		rawPath := strings.TrimSuffix(resolved.EscapedPath(), "/") + "/" + strings.TrimPrefix(ref.EscapedPath(), "/")
+		resolved.Path = strings.TrimSuffix(resolved.Path, "/") + "/" + strings.TrimPrefix(ref.Path, "/")
+		resolved.RawPath = rawPath
+	}
+	if ref.RawQuery != "" {
+		if resolved.RawQuery != "" {
+			resolved.RawQuery += "&"
+		}
+		resolved.RawQuery += ref.RawQuery
+	}
+	resolved.Fragment = ref.Fragment
+	return resolved.String(), nil
+}
@@ -0,0 +1,89 @@
+package go_requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		params   map[string]string
+		want     string
+		wantErr  bool
+	}{
+		{name: "single", template: "/pet/{petId}", params: map[string]string{"petId": "42"}, want: "/pet/42"},
+		{name: "several", template: "/user/{name}/pets/{id}?a={b}", params: map[string]string{"name": "john doe", "id": "a/b"}, want: "/user/john%20doe/pets/a%2Fb?a={b}"},
+		{name: "dot segments", template: "/users/{id}/profile/{sub}", params: map[string]string{"id": "..", "sub": "."}, want: "/users/%2E%2E/profile/%2E"},
+		{name: "dots", template: "/files/{name}", params: map[string]string{"name": "..."}, want: "/files/..."},
+		{name: "missing", template: "/pet/{petId}", params: map[string]string{}, wantErr: true},
+		{name: "unclosed", template: "/pet/{petId", params: map[string]string{"petId": "1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandPath(tt.template, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resolveURL(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		url  string
+		want string
+	}{
+		{name: "no base", base: "", url: "/pet", want: "/pet"},
+		{name: "absolute", base: "https://api.example.com/v2", url: "http://other.example.com/pet", want: "http://other.example.com/pet"},
+		{name: "leading slash", base: "https://api.example.com/v2", url: "/pet/findByTags", want: "https://api.example.com/v2/pet/findByTags"},
+		{name: "relative", base: "https://api.example.com/v2/", url: "pet", want: "https://api.example.com/v2/pet"},
+		{name: "escaped segment", base: "https://api.example.com/v2", url: "/files/a%2Fb", want: "https://api.example.com/v2/files/a%2Fb"},
+		{name: "queries", base: "https://api.example.com/v2?key=1", url: "/pet?status=sold", want: "https://api.example.com/v2/pet?key=1&status=sold"},
+		{name: "empty path", base: "https://api.example.com/v2", url: "", want: "https://api.example.com/v2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveURL(tt.base, tt.url)
+			if err != nil {
+				t.Fatalf("resolveURL() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_goHTTPClient_SetBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.EscapedPath()))
+	}))
+	defer server.Close()
+
+	builder := NewBuilder()
+	builder.SetBaseURL(server.URL + "/v2")
+	client := builder.Build()
+	res, err := client.Get("/pet/findByTags")
+	if err != nil || res.String() != "/v2/pet/findByTags" {
+		t.Errorf("Get() = %v, %v", res, err)
+	}
+	res, err = client.Do(NewRequest(http.MethodGet, "/user/{name}/pets").SetPathParam("name", "a/b c"))
+	if err != nil || res.String() != "/v2/user/a%2Fb%20c/pets" {
+		t.Errorf("Do() = %v, %v", res, err)
+	}
+	res, err = client.Do(NewRequest(http.MethodGet, "/users/{id}/profile").SetPathParam("id", ".."))
+	if err != nil || res.String() != "/v2/users/%2E%2E/profile" {
+		t.Errorf("Do() = %v, %v", res, err)
+	}
+	if _, err = client.Do(NewRequest(http.MethodGet, "/pet/{petId}").SetPathParams(map[string]string{"id": "1"})); err == nil {
+		t.Errorf("Do() expected an error for a missing path param")
+	}
+}